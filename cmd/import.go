@@ -0,0 +1,187 @@
+/*
+Copyright 2024 pdok.nl.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/PDOK/uptime-operator/internal/service"
+	traefikio "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const commandImport = "import"
+
+// importSettings what to do with the checks matched to ingress routes by the 'import' command. When
+// neither is set, the matches are only reported.
+type importSettings struct {
+	PatchFile string // write the annotations to this file, to apply using "kubectl apply --server-side"
+	Write     bool   // add the annotations to the ingress routes directly
+}
+
+// runImport matches the checks with the uptime provider which aren't managed by the operator to the ingress
+// routes in the cluster (from the kubeconfig) and reports the annotations to add to the routes. Depending on
+// the settings the annotations are written to a patch file and/or the routes. The checks are only adopted when
+// the annotations are written to the routes directly.
+func runImport(ctx context.Context, uptimeCheckService *service.UptimeCheckService, namespaces []string,
+	output string, settings importSettings, out io.Writer) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format '%s', expected 'text' or 'json'", output)
+	}
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	routes, err := listRoutes(ctx, k8sClient, namespaces)
+	if err != nil {
+		return err
+	}
+	importRoutes := make([]service.ImportRoute, 0, len(routes))
+	for _, name := range slices.Sorted(maps.Keys(routes)) {
+		importRoute := service.ImportRoute{Name: name, Annotations: routes[name].GetAnnotations()}
+		for _, route := range routes[name].Spec.Routes {
+			importRoute.Endpoints = append(importRoute.Endpoints, service.EndpointsOfRule(route.Match)...)
+		}
+		importRoutes = append(importRoutes, importRoute)
+	}
+	result, err := uptimeCheckService.Import(ctx, importRoutes)
+	if err != nil {
+		return err
+	}
+	if output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(result)
+	} else {
+		printImport(result, out)
+	}
+	if err != nil || len(result.Matched) == 0 {
+		return err
+	}
+
+	if settings.PatchFile != "" {
+		if err = writePatchFile(settings.PatchFile, result.Matched, routes); err != nil {
+			return err
+		}
+	}
+	if !settings.Write {
+		// adopting the checks is left to the operator (see -adoption-policy), since the patch file may
+		// never be applied, in which case adopted checks would be considered orphaned
+		return nil
+	}
+	// adopt the checks before adding the annotations, to prevent the operator from creating duplicates
+	for _, imported := range result.Matched {
+		if err = uptimeCheckService.Adopt(ctx, imported); err != nil {
+			return fmt.Errorf("failed to adopt check %s: %w", imported.ProviderID, err)
+		}
+		route := routes[imported.Route]
+		patch := client.MergeFrom(route.DeepCopy())
+		annotations := route.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		maps.Copy(annotations, imported.Annotations)
+		route.SetAnnotations(annotations)
+		if err = k8sClient.Patch(ctx, route, patch); err != nil {
+			return fmt.Errorf("failed to add annotations of check %s to ingress route %s: %w", imported.ProviderID, imported.Route, err)
+		}
+	}
+	return nil
+}
+
+// listRoutes returns all ingress routes (by namespace/name) in the given namespaces
+func listRoutes(ctx context.Context, k8sClient client.Client, namespaces []string) (map[string]*traefikio.IngressRoute, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{""} // all namespaces
+	}
+	result := make(map[string]*traefikio.IngressRoute)
+	for _, namespace := range namespaces {
+		routes := &traefikio.IngressRouteList{}
+		if err := k8sClient.List(ctx, routes, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		for i := range routes.Items {
+			result[client.ObjectKeyFromObject(&routes.Items[i]).String()] = &routes.Items[i]
+		}
+	}
+	return result, nil
+}
+
+// writePatchFile writes the annotations of the imported checks as partial ingress routes (YAML), which
+// can be applied to the cluster using "kubectl apply --server-side -f <file>"
+func writePatchFile(path string, matched []service.ImportedCheck, routes map[string]*traefikio.IngressRoute) error {
+	annotationsByRoute := make(map[string]map[string]string)
+	omittedByRoute := make(map[string][]string)
+	for _, imported := range matched {
+		if annotationsByRoute[imported.Route] == nil {
+			annotationsByRoute[imported.Route] = make(map[string]string)
+		}
+		maps.Copy(annotationsByRoute[imported.Route], imported.Annotations)
+		for _, omitted := range imported.Omitted {
+			omittedByRoute[imported.Route] = append(omittedByRoute[imported.Route],
+				fmt.Sprintf("# TODO add manually for check %s, may contain secrets: %s\n", imported.ProviderID, omitted))
+		}
+	}
+	var buf bytes.Buffer
+	for _, name := range slices.Sorted(maps.Keys(annotationsByRoute)) {
+		document, err := yaml.Marshal(map[string]any{
+			"apiVersion": traefikio.SchemeGroupVersion.String(),
+			"kind":       "IngressRoute",
+			"metadata": map[string]any{
+				"name":        routes[name].GetName(),
+				"namespace":   routes[name].GetNamespace(),
+				"annotations": annotationsByRoute[name],
+			},
+		})
+		if err != nil {
+			return err
+		}
+		buf.WriteString("---\n")
+		for _, todo := range omittedByRoute[name] {
+			buf.WriteString(todo)
+		}
+		buf.Write(document)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+func printImport(result *service.Import, out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Import: %d matched, %d unmatched.\n\n", len(result.Matched), len(result.Unmatched))
+	for _, imported := range result.Matched {
+		_, _ = fmt.Fprintf(out, "+ import %s '%s' (%s) into route %s\n", imported.ProviderID, imported.Name, imported.URL, imported.Route)
+		for _, key := range slices.Sorted(maps.Keys(imported.Annotations)) {
+			_, _ = fmt.Fprintf(out, "    %s: %s\n", key, imported.Annotations[key])
+		}
+		if len(imported.Omitted) > 0 {
+			_, _ = fmt.Fprintf(out, "    TODO add manually, may contain secrets (e.g. reference a Secret): %s\n",
+				strings.Join(imported.Omitted, ", "))
+		}
+	}
+	for _, unmatched := range result.Unmatched {
+		_, _ = fmt.Fprintf(out, "? unmatched %s '%s' (%s): %s\n", unmatched.ProviderID, unmatched.Name, unmatched.URL, unmatched.Reason)
+	}
+}
@@ -19,8 +19,12 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"slices"
+	"time"
 
+	m "github.com/PDOK/uptime-operator/internal/model"
 	"github.com/PDOK/uptime-operator/internal/service"
 	p "github.com/PDOK/uptime-operator/internal/service/providers"
 	"github.com/PDOK/uptime-operator/internal/service/providers/betterstack"
@@ -71,11 +75,29 @@ func main() {
 	var slackChannel string
 	var slackWebhookURL string
 	var enableDeletes bool
+	var deleteOrphanedChecks bool
+	var ownerTag string
+	var dryRun bool
+	var output string
+	var importPatchFile string
+	var importWrite bool
 	var uptimeProvider string
+	var adoptionPolicy string
+	var hashKey string
+	var defaultRegions util.SliceFlag
+	var defaultResponseTimeThreshold time.Duration
+	var defaultAlertAfterFailures int
+	var defaultRecoveryPeriod time.Duration
+	var startupSyncConcurrency int
+	var maxConcurrentReconciles int
+	var uptimeProviderConcurrency int
+	var startupSyncRateLimit float64
 	var pingdomAPIToken string
+	var pingdomTimeout time.Duration
 	var pingdomAlertUserIDs util.SliceFlag
 	var pingdomAlertIntegrationIDs util.SliceFlag
 	var betterstackAPIToken string
+	var betterstackTimeout time.Duration
 
 	// Default kubebuilder
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080",
@@ -91,6 +113,11 @@ func main() {
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers.")
 	flag.BoolVar(&enableDeletes, "enable-deletes", false,
 		"Allow the operator to delete checks from the uptime provider when ingress routes are removed.")
+	flag.BoolVar(&deleteOrphanedChecks, "delete-orphaned-checks", false,
+		"Delete checks with the owner tag which don't belong to any ingress route when the operator starts. "+
+			"Requires 'enable-deletes', 'owner-tag' and the startup sync.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Only log (and post to Slack) the mutations of uptime checks, without executing these with the uptime provider.")
 
 	// General uptime-operator
 	flag.Var(&namespaces, "namespace", "Namespace(s) to watch for changes. "+
@@ -101,6 +128,38 @@ func main() {
 		"The webhook URL required to post messages to the given Slack channel.")
 	flag.StringVar(&uptimeProvider, "uptime-provider", "mock",
 		"Name of the (SaaS) uptime monitoring provider to use.")
+	flag.StringVar(&ownerTag, "owner-tag", "",
+		"Tag to add to all checks of this operator instance (e.g. the name of the cluster), to tell these apart from "+
+			"checks of other instances using the same uptime provider account.")
+	flag.StringVar(&adoptionPolicy, "adoption-policy", string(m.AdoptionPolicyNone),
+		"Adopt an existing check which isn't managed by the operator instead of creating a new check, when it matches "+
+			"according to this policy. Either 'none', 'url' (same type and URL) or 'name-and-url' (same name, type and URL).")
+	flag.StringVar(&hashKey, "hash-key", "",
+		"Key to sign the hashes of applied checks (in the last-applied annotation) with, so these don't give away "+
+			"secret values like API keys. When not provided the API token of the uptime provider is used.")
+	flag.Var(&defaultRegions, "default-region",
+		"Region(s) from which uptime checks are executed, unless specified otherwise on the ingress route. "+
+			"Specify this flag multiple times for each region. When not provided the default regions of the uptime provider are used.")
+	flag.DurationVar(&defaultResponseTimeThreshold, "default-response-time-threshold", 0,
+		"Alert when the response time exceeds this duration, unless specified otherwise on the ingress route. "+
+			"When not provided the default of the uptime provider is used.")
+	flag.IntVar(&defaultAlertAfterFailures, "default-alert-after-failures", 0,
+		"Alert after this number of consecutive failures, unless specified otherwise on the ingress route. "+
+			"When not provided the default of the uptime provider is used.")
+	flag.DurationVar(&defaultRecoveryPeriod, "default-recovery-period", 0,
+		"Consider a check up again after it succeeded for this duration, unless specified otherwise on the ingress route. "+
+			"When not provided the default of the uptime provider is used.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"Max number of ingress routes to reconcile concurrently.")
+	flag.IntVar(&uptimeProviderConcurrency, "uptime-provider-concurrency", 5,
+		"Max number of concurrent calls to the uptime provider, regardless of the number of ingress routes "+
+			"reconciled concurrently. Set to 0 for no limit.")
+	flag.IntVar(&startupSyncConcurrency, "startup-sync-concurrency", 10,
+		"Number of ingress routes to synchronize concurrently with the uptime provider when the operator starts. "+
+			"Set to 0 to disable the startup sync, in which case ingress routes are only synchronized one-by-one.")
+	flag.Float64Var(&startupSyncRateLimit, "startup-sync-rate-limit", 10,
+		"Max number of ingress routes per second to synchronize with the uptime provider when the operator starts. "+
+			"Set to 0 for no limit.")
 
 	// Pingdom specific
 	flag.StringVar(&pingdomAPIToken, "pingdom-api-token", "",
@@ -109,10 +168,27 @@ func main() {
 		"One or more IDs of Pingdom users to alert. Only applies when 'uptime-provider' is 'pingdom'")
 	flag.Var(&pingdomAlertIntegrationIDs, "pingdom-alert-integration-ids",
 		"One or more IDs of Pingdom integrations (like slack channels) to alert. Only applies when 'uptime-provider' is 'pingdom'")
+	flag.DurationVar(&pingdomTimeout, "pingdom-timeout", p.DefaultTimeout,
+		"Max duration of a single call to the Pingdom API, including retries. Only applies when 'uptime-provider' is 'pingdom'")
 
 	// Better Stack specific
 	flag.StringVar(&betterstackAPIToken, "betterstack-api-token", "",
 		"The API token to authenticate with Better Stack. Only applies when 'uptime-provider' is 'betterstack'")
+	flag.DurationVar(&betterstackTimeout, "betterstack-timeout", p.DefaultTimeout,
+		"Max duration of a single call to the Better Stack API, including retries. Only applies when 'uptime-provider' is 'betterstack'")
+
+	// Subcommands, with their own flags
+	planFlags := flag.NewFlagSet(commandPlan, flag.ExitOnError)
+	planFlags.StringVar(&output, "output", "text",
+		"Output format, either 'text' or 'json'.")
+	importFlags := flag.NewFlagSet(commandImport, flag.ExitOnError)
+	importFlags.StringVar(&output, "output", "text",
+		"Output format, either 'text' or 'json'.")
+	importFlags.StringVar(&importPatchFile, "import-patch-file", "",
+		"File to write the annotations of imported checks to, as partial ingress routes to apply with "+
+			"'kubectl apply --server-side'. Imported checks aren't adopted, see 'adoption-policy'.")
+	importFlags.BoolVar(&importWrite, "import-write", false,
+		"Add the annotations of imported checks to the ingress routes directly. Imported checks are adopted.")
 
 	opts := zap.Options{
 		Development: true,
@@ -120,14 +196,16 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	if err := ff.Parse(flag.CommandLine, os.Args[1:], ff.WithEnvVarNoPrefix()); err != nil {
+	// optional subcommand, by default the operator itself is started
+	command, err := parseFlags(os.Args[1:], planFlags, importFlags)
+	if err != nil {
 		setupLog.Error(err, "unable to parse flags")
 		os.Exit(1)
 	}
 
-	mgr, err := createManager(enableHTTP2, metricsAddr, secureMetrics, probeAddr, enableLeaderElection, namespaces)
+	adoption, err := m.ParseAdoptionPolicy(adoptionPolicy)
 	if err != nil {
-		setupLog.Error(err, "unable to start manager")
+		setupLog.Error(err, "Unable to parse 'adoption-policy' flag")
 		os.Exit(1)
 	}
 
@@ -146,26 +224,87 @@ func main() {
 			setupLog.Error(err, "Unable to parse 'pingdom-alert-integration-ids' flag")
 			os.Exit(1)
 		}
+		if hashKey == "" {
+			hashKey = pingdomAPIToken
+		}
 		uptimeProviderSettings = pingdom.Settings{
 			APIToken:       pingdomAPIToken,
 			UserIDs:        alertUserIDs,
 			IntegrationIDs: alertIntegrationIDs,
+			Timeout:        pingdomTimeout,
 		}
 	} else if uptimeProviderID == p.ProviderBetterStack {
+		if hashKey == "" {
+			hashKey = betterstackAPIToken
+		}
 		uptimeProviderSettings = betterstack.Settings{
 			APIToken: betterstackAPIToken,
+			Timeout:  betterstackTimeout,
+		}
+	}
+
+	uptimeCheckService := service.New(
+		service.WithProviderAndSettings(uptimeProviderID, uptimeProviderSettings),
+		service.WithSlack(slackWebhookURL, slackChannel),
+		service.WithDeletes(enableDeletes),
+		service.WithOwnerTag(ownerTag),
+		service.WithOrphanDeletes(deleteOrphanedChecks),
+		service.WithDryRun(dryRun),
+		service.WithAdoptionPolicy(adoption),
+		service.WithHashKey(hashKey),
+		service.WithProviderConcurrency(uptimeProviderConcurrency),
+		service.WithDefaults(m.CheckDefaults{
+			Regions:               defaultRegions,
+			ResponseTimeThreshold: defaultResponseTimeThreshold,
+			AlertAfterFailures:    defaultAlertAfterFailures,
+			RecoveryPeriod:        defaultRecoveryPeriod,
+		}),
+	)
+
+	if err := uptimeCheckService.ValidateDefaultRegions(); err != nil {
+		setupLog.Error(err, "Unable to parse 'default-region' flag")
+		os.Exit(1)
+	}
+
+	switch command {
+	case commandPlan:
+		if err := runPlan(ctrl.SetupSignalHandler(), uptimeCheckService, namespaces, output, os.Stdout); err != nil {
+			setupLog.Error(err, "unable to plan")
+			os.Exit(1)
+		}
+		return
+	case commandImport:
+		settings := importSettings{PatchFile: importPatchFile, Write: importWrite}
+		if err := runImport(ctrl.SetupSignalHandler(), uptimeCheckService, namespaces, output, settings, os.Stdout); err != nil {
+			setupLog.Error(err, "unable to import")
+			os.Exit(1)
+		}
+		return
+	}
+
+	mgr, err := createManager(enableHTTP2, metricsAddr, secureMetrics, probeAddr, enableLeaderElection, namespaces)
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	var startupSync *controller.StartupSyncSettings
+	if startupSyncConcurrency > 0 {
+		startupSync = &controller.StartupSyncSettings{
+			Concurrency: startupSyncConcurrency,
+			RateLimit:   startupSyncRateLimit,
 		}
 	}
 
 	// Setup controller
 	if err = (&controller.IngressRouteReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		UptimeCheckService: service.New(
-			service.WithProviderAndSettings(uptimeProviderID, uptimeProviderSettings),
-			service.WithSlack(slackWebhookURL, slackChannel),
-			service.WithDeletes(enableDeletes),
-		),
+		Client:                  mgr.GetClient(),
+		APIReader:               mgr.GetAPIReader(),
+		Scheme:                  mgr.GetScheme(),
+		Recorder:                mgr.GetEventRecorderFor(m.OperatorName),
+		UptimeCheckService:      uptimeCheckService,
+		StartupSync:             startupSync,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "IngressRoute")
 		os.Exit(1)
@@ -244,3 +383,31 @@ func createManager(enableHTTP2 bool, metricsAddr string, secureMetrics bool, pro
 
 	return ctrl.NewManager(ctrl.GetConfigOrDie(), managerOpts)
 }
+
+// parseFlags parses the (global) flags of the operator, followed by an optional subcommand with its own flags.
+// Global flags may also be specified after the subcommand. Returns the name of the subcommand, if any.
+func parseFlags(args []string, commands ...*flag.FlagSet) (string, error) {
+	if err := ff.Parse(flag.CommandLine, args, ff.WithEnvVarNoPrefix()); err != nil {
+		return "", err
+	}
+	if flag.NArg() == 0 {
+		return "", nil
+	}
+	i := slices.IndexFunc(commands, func(command *flag.FlagSet) bool { return command.Name() == flag.Arg(0) })
+	if i < 0 {
+		return "", fmt.Errorf("unknown command '%s', expected '%s' or '%s'", flag.Arg(0), commandPlan, commandImport)
+	}
+	command := commands[i]
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		if command.Lookup(f.Name) == nil {
+			command.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	if err := command.Parse(flag.Args()[1:]); err != nil {
+		return "", err
+	}
+	if command.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %v for command '%s'", command.Args(), command.Name())
+	}
+	return command.Name(), nil
+}
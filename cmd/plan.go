@@ -0,0 +1,92 @@
+/*
+Copyright 2024 pdok.nl.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/PDOK/uptime-operator/internal/service"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const commandPlan = "plan"
+
+// runPlan prints the mutations of uptime checks needed to bring the uptime provider in line with the ingress
+// routes in the cluster (from the kubeconfig), without changing anything. Either human-readable or as JSON.
+func runPlan(ctx context.Context, uptimeCheckService *service.UptimeCheckService, namespaces []string,
+	output string, out io.Writer) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format '%s', expected 'text' or 'json'", output)
+	}
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	routes, err := listRoutes(ctx, k8sClient, namespaces)
+	if err != nil {
+		return err
+	}
+	routeAnnotations := make(map[string]map[string]string, len(routes))
+	for name, route := range routes {
+		routeAnnotations[name] = route.GetAnnotations()
+	}
+	plan, err := uptimeCheckService.Plan(ctx, routeAnnotations)
+	if err != nil {
+		return err
+	}
+	if output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+	printPlan(plan, out)
+	return nil
+}
+
+func printPlan(plan *service.Plan, out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Plan: %d to create, %d to update, %d unchanged, %d orphaned, %d invalid.\n\n",
+		len(plan.Create), len(plan.Update), len(plan.Unchanged), len(plan.Orphaned), len(plan.Invalid))
+	for _, check := range plan.Create {
+		_, _ = fmt.Fprintf(out, "+ create %s '%s' (route %s)\n", check.ID, check.Name, check.Route)
+		printWarnings(check, out)
+	}
+	for _, check := range plan.Update {
+		_, _ = fmt.Fprintf(out, "~ update %s '%s' (route %s)\n", check.ID, check.Name, check.Route)
+		for _, change := range check.Changes {
+			current, _ := json.Marshal(change.Current)
+			desired, _ := json.Marshal(change.Desired)
+			_, _ = fmt.Fprintf(out, "    %s: %s => %s\n", change.Field, current, desired)
+		}
+		printWarnings(check, out)
+	}
+	for _, id := range plan.Orphaned {
+		_, _ = fmt.Fprintf(out, "- orphaned %s (deleted when -delete-orphaned-checks is set)\n", id)
+	}
+	for _, route := range plan.Invalid {
+		_, _ = fmt.Fprintf(out, "! invalid route %s: %s\n", route.Route, route.Error)
+	}
+}
+
+func printWarnings(check service.PlannedCheck, out io.Writer) {
+	for _, warning := range check.Warnings {
+		_, _ = fmt.Fprintf(out, "    warning: %s\n", warning)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+MIT License
+
+Copyright (c) 2024 Publieke Dienstverlening op de Kaart
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	traefikio "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// checkIDIndex field index on the uptime check ID(s) of ingress routes, used to detect duplicate IDs
+const checkIDIndex = "metadata.annotations." + m.AnnotationID
+
+// duplicate an ingress route which has an uptime check ID in common with another ingress route
+type duplicate struct {
+	checkID    string
+	otherRoute types.NamespacedName
+}
+
+// indexCheckIDs extracts the uptime check ID(s) of an ingress route for the checkIDIndex. Includes the IDs of
+// additional checks for string-not-contains assertions, in case the uptime provider splits these off.
+func indexCheckIDs(obj client.Object) []string {
+	annotations := obj.GetAnnotations()
+	if _, ignore := annotations[m.AnnotationIgnore]; ignore {
+		return nil
+	}
+	checks, _ := m.NewUptimeChecks(context.Background(), obj.GetName(), annotations, nil)
+	var result []string
+	for _, check := range checks {
+		result = append(result, check.ID)
+		if notContainsID, ok := check.NotContainsCheckID(); ok {
+			result = append(result, notContainsID)
+		}
+	}
+	return result
+}
+
+// findDuplicate returns a duplicate when the given ingress route has an uptime check ID in common with
+// another ingress route which takes precedence. The oldest ingress route takes precedence, so the
+// uptime check of an existing route won't be hijacked by a newly created route.
+func (r *IngressRouteReconciler) findDuplicate(ctx context.Context, obj client.Object) (*duplicate, error) {
+	for _, checkID := range indexCheckIDs(obj) {
+		routes := &traefikio.IngressRouteList{}
+		if err := r.List(ctx, routes, client.MatchingFields{checkIDIndex: checkID}); err != nil {
+			return nil, err
+		}
+		for i := range routes.Items {
+			other := &routes.Items[i]
+			if other.GetUID() == obj.GetUID() || !other.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+			if takesPrecedence(other, obj) {
+				return &duplicate{checkID: checkID, otherRoute: client.ObjectKeyFromObject(other)}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func takesPrecedence(obj client.Object, other client.Object) bool {
+	created, otherCreated := obj.GetCreationTimestamp(), other.GetCreationTimestamp()
+	if !created.Equal(&otherCreated) {
+		return created.Before(&otherCreated)
+	}
+	return client.ObjectKeyFromObject(obj).String() < client.ObjectKeyFromObject(other).String()
+}
+
+// mapToDuplicates enqueues the ingress routes which share an uptime check ID with the given route,
+// so a route which was refused earlier (because of a duplicate ID) is retried when the other route changes.
+func (r *IngressRouteReconciler) mapToDuplicates(ctx context.Context, obj client.Object) []reconcile.Request {
+	var result []reconcile.Request
+	for _, checkID := range indexCheckIDs(obj) {
+		routes := &traefikio.IngressRouteList{}
+		if err := r.List(ctx, routes, client.MatchingFields{checkIDIndex: checkID}); err != nil {
+			continue
+		}
+		for _, other := range routes.Items {
+			request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&other)}
+			if other.GetUID() != obj.GetUID() && !slices.Contains(result, request) {
+				result = append(result, request)
+			}
+		}
+	}
+	return result
+}
@@ -26,15 +26,20 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	m "github.com/PDOK/uptime-operator/internal/model"
 	"github.com/PDOK/uptime-operator/internal/service"
 	traefikio "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	controllerruntime "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -45,11 +50,28 @@ import (
 type IngressRouteReconciler struct {
 	client.Client
 	Scheme             *runtime.Scheme
+	Recorder           record.EventRecorder
 	UptimeCheckService *service.UptimeCheckService
+
+	// APIReader reads referenced Secrets and ConfigMaps directly from the API server, so their contents
+	// aren't cached by the operator. Defaults to the (cached) client.
+	APIReader client.Reader
+
+	// MaxConcurrentReconciles max number of ingress routes to reconcile concurrently, defaults to 1
+	MaxConcurrentReconciles int
+
+	// StartupSync when not nil all ingress routes are synchronized in bulk when the operator starts,
+	// before regular (event-driven) reconciliation starts.
+	StartupSync *StartupSyncSettings
+
+	startupSynced      chan struct{} // closed once the startup sync is done
+	startupSyncResults sync.Map      // startupSyncResult by route, see skipAfterStartupSync
 }
 
-//+kubebuilder:rbac:groups=traefik.io,resources=ingressroutes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=traefik.io,resources=ingressroutes,verbs=get;list;watch;patch
 //+kubebuilder:rbac:groups=traefik.io,resources=ingressroutes/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -57,19 +79,88 @@ type IngressRouteReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
 func (r *IngressRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if err := r.awaitStartupSync(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
 	ingressRoute, err := r.getIngressRoute(ctx, req)
 	if err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	if result, skip := r.skipAfterStartupSync(ingressRoute); skip {
+		return result, nil
+	}
+	return r.reconcileIngressRoute(ctx, ingressRoute)
+}
+
+func (r *IngressRouteReconciler) reconcileIngressRoute(ctx context.Context, ingressRoute client.Object) (ctrl.Result, error) {
+	dup, err := r.findDuplicate(ctx, ingressRoute)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	shouldContinue, err := finalizeIfNecessary(ctx, r.Client, ingressRoute, m.AnnotationFinalizer, func() error {
-		r.UptimeCheckService.Mutate(ctx, m.Delete, ingressRoute.GetName(), ingressRoute.GetAnnotations())
+		if dup == nil { // never delete a check which belongs to another route
+			r.UptimeCheckService.Mutate(ctx, m.Delete, ingressRoute.GetName(), ingressRoute.GetAnnotations(), nil)
+		}
 		return nil
 	})
 	if !shouldContinue || err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	r.UptimeCheckService.Mutate(ctx, m.CreateOrUpdate, ingressRoute.GetName(), ingressRoute.GetAnnotations())
-	return ctrl.Result{}, nil
+	if dup != nil {
+		r.reportDuplicate(ctx, ingressRoute, dup)
+		return ctrl.Result{}, nil
+	}
+	result := r.UptimeCheckService.Mutate(ctx, m.CreateOrUpdate, ingressRoute.GetName(), ingressRoute.GetAnnotations(),
+		newReferenceResolver(r.referenceReader(), ingressRoute))
+	r.reportWarnings(ingressRoute, result.Warnings)
+	if result.LastApplied != nil {
+		if err = r.storeLastApplied(ctx, ingressRoute, *result.LastApplied); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	return ctrl.Result{RequeueAfter: result.RequeueAfter}, nil
+}
+
+func (r *IngressRouteReconciler) referenceReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// storeLastApplied keeps track of the applied uptime checks on the ingress route itself
+func (r *IngressRouteReconciler) storeLastApplied(ctx context.Context, obj client.Object, lastApplied m.LastApplied) error {
+	annotations := obj.GetAnnotations()
+	if annotations[m.AnnotationLastApplied] == lastApplied.String() {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[m.AnnotationLastApplied] = lastApplied.String()
+	obj.SetAnnotations(annotations)
+	return r.Patch(ctx, obj, patch)
+}
+
+func (r *IngressRouteReconciler) reportDuplicate(ctx context.Context, obj client.Object, dup *duplicate) {
+	route := client.ObjectKeyFromObject(obj).String()
+	otherRoute := dup.otherRoute.String()
+	if r.Recorder != nil {
+		r.Recorder.Event(obj, corev1.EventTypeWarning, "DuplicateUptimeCheckID",
+			fmt.Sprintf("uptime check ID '%s' is already in use by ingress route %s, refusing to mutate uptime check(s)",
+				dup.checkID, otherRoute))
+	}
+	r.UptimeCheckService.ReportDuplicateID(ctx, dup.checkID, route, otherRoute)
+}
+
+func (r *IngressRouteReconciler) reportWarnings(obj client.Object, warnings []string) {
+	if r.Recorder == nil {
+		return
+	}
+	for _, warning := range warnings {
+		r.Recorder.Event(obj, corev1.EventTypeWarning, "UptimeCheckWarning", warning)
+	}
 }
 
 func (r *IngressRouteReconciler) getIngressRoute(ctx context.Context, req ctrl.Request) (client.Object, error) {
@@ -117,11 +208,38 @@ func finalizeIfNecessary(ctx context.Context, c client.Client, obj client.Object
 func (r *IngressRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	preCondition := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})
 
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &traefikio.IngressRoute{}, checkIDIndex, indexCheckIDs)
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &traefikio.IngressRoute{}, referenceIndex, indexReferences)
+	if err != nil {
+		return err
+	}
+	if r.StartupSync != nil {
+		r.startupSynced = make(chan struct{})
+		if err = mgr.Add(&startupSync{reconciler: r, settings: *r.StartupSync}); err != nil {
+			return err
+		}
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(m.OperatorName).
+		WithOptions(controllerruntime.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Watches(
 			&traefikio.IngressRoute{}, // watch "traefik.io/v1alpha1" ingresses
 			&handler.EnqueueRequestForObject{},
 			builder.WithPredicates(preCondition)).
+		Watches(
+			&traefikio.IngressRoute{}, // also revisit ingresses with the same uptime check ID(s)
+			handler.EnqueueRequestsFromMapFunc(r.mapToDuplicates),
+			builder.WithPredicates(preCondition)).
+		Watches(
+			&corev1.Secret{}, // revisit ingresses which reference a changed secret, only caches metadata
+			handler.EnqueueRequestsFromMapFunc(r.mapToReferencingRoutes(m.ReferenceSecret)),
+			builder.OnlyMetadata).
+		Watches(
+			&corev1.ConfigMap{}, // revisit ingresses which reference a changed config map, only caches metadata
+			handler.EnqueueRequestsFromMapFunc(r.mapToReferencingRoutes(m.ReferenceConfigMap)),
+			builder.OnlyMetadata).
 		Complete(r)
 }
@@ -27,6 +27,8 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	m "github.com/PDOK/uptime-operator/internal/model"
 	"github.com/PDOK/uptime-operator/internal/service"
@@ -36,6 +38,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -64,6 +67,31 @@ func (m *testUptimeProvider) DeleteCheck(_ context.Context, check m.UptimeCheck)
 	return nil
 }
 
+// indexedClient emulates the field index on uptime check IDs, which is
+// normally provided by the cache of the manager (not used in this test).
+type indexedClient struct {
+	client.Client
+}
+
+func (c indexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+	if listOpts.FieldSelector == nil {
+		return c.Client.List(ctx, list, opts...)
+	}
+	checkID, ok := listOpts.FieldSelector.RequiresExactMatch(checkIDIndex)
+	if !ok {
+		return c.Client.List(ctx, list, opts...)
+	}
+	routes := list.(*traefikio.IngressRouteList)
+	if err := c.Client.List(ctx, routes); err != nil {
+		return err
+	}
+	routes.Items = slices.DeleteFunc(routes.Items, func(route traefikio.IngressRoute) bool {
+		return !slices.Contains(indexCheckIDs(&route), checkID)
+	})
+	return nil
+}
+
 var ingressRouteWithUptimeCheck = &traefikio.IngressRoute{
 	TypeMeta: v1.TypeMeta{},
 	ObjectMeta: v1.ObjectMeta{
@@ -105,7 +133,7 @@ var _ = Describe("IngressRoute Controller", func() {
 		It("Should successfully create + update an uptime check for an ingress route", func() {
 			testProvider := newTestUptimeProvider()
 			controllerReconciler := &IngressRouteReconciler{
-				Client:             k8sClient,
+				Client:             indexedClient{k8sClient},
 				Scheme:             k8sClient.Scheme(),
 				UptimeCheckService: service.New(service.WithProvider(testProvider)),
 			}
@@ -128,10 +156,11 @@ var _ = Describe("IngressRoute Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(testProvider.checks).To(ContainElement(m.UptimeCheck{
 				ID:       "y45735y375",
+				Type:     m.CheckTypeHTTP,
 				URL:      "https://test.example",
 				Name:     "Test uptime check",
 				Tags:     []string{"managed-by-uptime-operator"},
-				Interval: 1,
+				Interval: time.Minute,
 			}))
 
 			By("Fetching and updating IngressRoute (adding extra uptime annotation)")
@@ -148,11 +177,12 @@ var _ = Describe("IngressRoute Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(testProvider.checks).To(ContainElement(m.UptimeCheck{
 				ID:             "y45735y375",
+				Type:           m.CheckTypeHTTP,
 				URL:            "https://test.example",
 				Name:           "Test uptime check",
 				Tags:           []string{"managed-by-uptime-operator"},
 				StringContains: "OK",
-				Interval:       1,
+				Interval:       time.Minute,
 			}))
 
 			By("Reconciling the IngressRoute again to make sure it doesn't cause any side effects")
@@ -164,7 +194,7 @@ var _ = Describe("IngressRoute Controller", func() {
 		It("Should delete uptime check for an existing ingress route", func() {
 			testProvider := newTestUptimeProvider()
 			controllerReconciler := &IngressRouteReconciler{
-				Client:             k8sClient,
+				Client:             indexedClient{k8sClient},
 				Scheme:             k8sClient.Scheme(),
 				UptimeCheckService: service.New(service.WithProvider(testProvider), service.WithDeletes(true)),
 			}
@@ -174,11 +204,12 @@ var _ = Describe("IngressRoute Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(testProvider.checks).To(ContainElement(m.UptimeCheck{
 				ID:             "y45735y375",
+				Type:           m.CheckTypeHTTP,
 				URL:            "https://test.example",
 				Name:           "Test uptime check",
 				Tags:           []string{"managed-by-uptime-operator"},
 				StringContains: "OK",
-				Interval:       1,
+				Interval:       time.Minute,
 			}))
 
 			By("Delete IngressRoute")
@@ -194,5 +225,34 @@ var _ = Describe("IngressRoute Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(testProvider.checks).To(BeEmpty())
 		})
+
+		It("Should refuse to mutate an ingress route with a duplicate uptime check ID", func() {
+			testProvider := newTestUptimeProvider()
+			controllerReconciler := &IngressRouteReconciler{
+				Client:             indexedClient{k8sClient},
+				Scheme:             k8sClient.Scheme(),
+				UptimeCheckService: service.New(service.WithProvider(testProvider)),
+			}
+
+			By("Creating two IngressRoutes with the same uptime check ID")
+			for _, name := range []string{"duplicate-a", "duplicate-b"} {
+				resource := ingressRouteWithUptimeCheck.DeepCopy()
+				resource.Name = name
+				resource.Annotations[m.AnnotationID] = "duplicate-id"
+				resource.Annotations[m.AnnotationName] = name
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+
+			By("Reconciling both IngressRoutes")
+			for _, name := range []string{"duplicate-a", "duplicate-b"} {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      name,
+					Namespace: testNamespace,
+				}})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(testProvider.checks).To(HaveLen(1))
+			Expect(testProvider.checks["duplicate-id"].Name).To(Equal("duplicate-a"))
+		})
 	})
 })
@@ -0,0 +1,117 @@
+/*
+MIT License
+
+Copyright (c) 2024 Publieke Dienstverlening op de Kaart
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	traefikio "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// referenceIndex field index on the Secrets and ConfigMaps referenced by ingress routes (as "kind/name"),
+// used to revisit ingress routes when a referenced Secret or ConfigMap changes (e.g. a rotated API key).
+const referenceIndex = "metadata.annotations." + m.AnnotationBase + "/references"
+
+// referenceResolver resolves references to Secrets and ConfigMaps in the annotations of an
+// ingress route. Only references to the namespace of the ingress route are allowed, so an ingress
+// route can't be used to read Secrets from other namespaces.
+type referenceResolver struct {
+	client    client.Reader
+	namespace string
+}
+
+func newReferenceResolver(c client.Reader, obj client.Object) *referenceResolver {
+	return &referenceResolver{client: c, namespace: obj.GetNamespace()}
+}
+
+func (r *referenceResolver) Resolve(ctx context.Context, ref m.Reference) (string, error) {
+	if ref.Namespace != "" && ref.Namespace != r.namespace {
+		return "", fmt.Errorf("reference to namespace %s not allowed, only references to namespace %s are", ref.Namespace, r.namespace)
+	}
+	key := types.NamespacedName{Namespace: r.namespace, Name: ref.Name}
+
+	var values map[string]string
+	switch ref.Kind {
+	case m.ReferenceSecret:
+		secret := &corev1.Secret{}
+		if err := r.client.Get(ctx, key, secret); err != nil {
+			return "", err
+		}
+		values = make(map[string]string, len(secret.Data))
+		for k, v := range secret.Data {
+			values[k] = string(v)
+		}
+	case m.ReferenceConfigMap:
+		configMap := &corev1.ConfigMap{}
+		if err := r.client.Get(ctx, key, configMap); err != nil {
+			return "", err
+		}
+		values = configMap.Data
+	}
+	value, ok := values[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in %s %s", ref.Key, ref.Kind, key)
+	}
+	return value, nil
+}
+
+// indexReferences extracts the Secrets and ConfigMaps referenced by an ingress route for the referenceIndex
+func indexReferences(obj client.Object) []string {
+	var result []string
+	for _, ref := range m.References(obj.GetAnnotations()) {
+		if ref.Namespace != "" && ref.Namespace != obj.GetNamespace() {
+			continue // not allowed anyway
+		}
+		result = append(result, referenceIndexValue(ref.Kind, ref.Name))
+	}
+	return result
+}
+
+func referenceIndexValue(kind m.ReferenceKind, name string) string {
+	return string(kind) + "/" + name
+}
+
+// mapToReferencingRoutes enqueues the ingress routes which reference the given Secret or ConfigMap
+func (r *IngressRouteReconciler) mapToReferencingRoutes(kind m.ReferenceKind) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		routes := &traefikio.IngressRouteList{}
+		err := r.List(ctx, routes, client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{referenceIndex: referenceIndexValue(kind, obj.GetName())})
+		if err != nil {
+			return nil
+		}
+		var result []reconcile.Request
+		for _, route := range routes.Items {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&route)})
+		}
+		return result
+	}
+}
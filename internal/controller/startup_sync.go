@@ -0,0 +1,168 @@
+/*
+MIT License
+
+Copyright (c) 2024 Publieke Dienstverlening op de Kaart
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	traefikio "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	"golang.org/x/time/rate"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// StartupSyncSettings settings of the bulk synchronization of all ingress routes when the operator starts
+type StartupSyncSettings struct {
+	// Concurrency max number of ingress routes synchronized concurrently
+	Concurrency int
+
+	// RateLimit max number of ingress routes synchronized per second, zero means no limit
+	RateLimit float64
+}
+
+// startupSync synchronizes all ingress routes with the uptime provider once, when the operator starts.
+// Checks at the uptime provider are listed once (see provider ID index), unchanged checks are skipped
+// (see last-applied hashes) and checks which don't belong to any ingress route anymore are removed (when
+// enabled). Regular (event-driven) reconciliation waits until the startup sync is done, and skips the
+// routes which are unchanged since (see skipAfterStartupSync).
+//
+// Note this doesn't compute one create/update/delete diff for all routes up front. Instead, routes are
+// reconciled one by one, with bounded concurrency and rate limiting, since each reconcile also needs to
+// store the last-applied state on its route and report warnings. The diff per check is determined by the
+// last-applied hashes and the (single) provider listing, so unchanged checks don't cause provider calls.
+type startupSync struct {
+	reconciler *IngressRouteReconciler
+	settings   StartupSyncSettings
+}
+
+// Start implements manager.Runnable
+func (s *startupSync) Start(ctx context.Context) error {
+	defer close(s.reconciler.startupSynced)
+	logger := log.FromContext(ctx).WithName("startup-sync")
+	ctx = log.IntoContext(ctx, logger)
+	start := time.Now()
+
+	routes := &traefikio.IngressRouteList{}
+	if err := s.reconciler.List(ctx, routes); err != nil {
+		logger.Error(err, "unable to list ingress routes, skipping startup sync")
+		return nil
+	}
+	// copy annotations up front, since these are modified while syncing
+	annotations := make(map[string]map[string]string, len(routes.Items))
+	for i := range routes.Items {
+		annotations[client.ObjectKeyFromObject(&routes.Items[i]).String()] = maps.Clone(routes.Items[i].GetAnnotations())
+	}
+	logger.Info("starting sync", "routes", len(routes.Items))
+
+	limit := rate.Inf
+	if s.settings.RateLimit > 0 {
+		limit = rate.Limit(s.settings.RateLimit)
+	}
+	limiter := rate.NewLimiter(limit, 1)
+	semaphore := make(chan struct{}, max(s.settings.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i := range routes.Items {
+		if err := limiter.Wait(ctx); err != nil {
+			break // shutting down
+		}
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(route *traefikio.IngressRoute) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			result, err := s.reconciler.reconcileIngressRoute(ctx, route)
+			if err != nil {
+				logger.Error(err, "unable to sync ingress route", "route", client.ObjectKeyFromObject(route))
+				return
+			}
+			s.reconciler.recordStartupSync(route, result)
+		}(&routes.Items[i])
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err := s.reconciler.UptimeCheckService.DeleteOrphanedChecks(ctx, annotations); err != nil {
+		logger.Error(err, "unable to remove orphaned checks")
+	}
+	logger.Info("finished sync", "routes", len(routes.Items), "duration", time.Since(start))
+	return nil
+}
+
+// awaitStartupSync blocks until the startup sync (when enabled) is done
+func (r *IngressRouteReconciler) awaitStartupSync(ctx context.Context) error {
+	if r.startupSynced == nil {
+		return nil
+	}
+	select {
+	case <-r.startupSynced:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startupSyncResult outcome of the startup sync of an ingress route, see skipAfterStartupSync
+type startupSyncResult struct {
+	resourceVersion string
+	requeueAt       time.Time // zero when the route doesn't need to be revisited
+}
+
+func (r *IngressRouteReconciler) recordStartupSync(route client.Object, result ctrl.Result) {
+	synced := startupSyncResult{resourceVersion: route.GetResourceVersion()}
+	if result.RequeueAfter > 0 {
+		synced.requeueAt = time.Now().Add(result.RequeueAfter)
+	}
+	r.startupSyncResults.Store(client.ObjectKeyFromObject(route), synced)
+}
+
+// skipAfterStartupSync whether the given ingress route is unchanged since the startup sync, in which case the
+// (initial) reconcile doesn't need to synchronize it again. The result revisits the route at its next maintenance
+// window transition, as determined by the startup sync.
+func (r *IngressRouteReconciler) skipAfterStartupSync(route client.Object) (ctrl.Result, bool) {
+	value, ok := r.startupSyncResults.LoadAndDelete(client.ObjectKeyFromObject(route))
+	if !ok {
+		return ctrl.Result{}, false
+	}
+	synced := value.(startupSyncResult)
+	if synced.resourceVersion != route.GetResourceVersion() {
+		return ctrl.Result{}, false
+	}
+	if synced.requeueAt.IsZero() {
+		return ctrl.Result{}, true
+	}
+	requeueAfter := time.Until(synced.requeueAt)
+	if requeueAfter <= 0 {
+		return ctrl.Result{}, false // transition is due, synchronize now
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, true
+}
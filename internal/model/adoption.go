@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// AdoptionPolicy enum of policies to adopt existing checks which aren't managed by the operator (yet), instead
+// of creating a new check for an ingress route.
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyNone never adopt unmanaged checks, always create new checks
+	AdoptionPolicyNone AdoptionPolicy = "none"
+
+	// AdoptionPolicyURL adopt an unmanaged check with the same type and URL
+	AdoptionPolicyURL AdoptionPolicy = "url"
+
+	// AdoptionPolicyNameAndURL adopt an unmanaged check with the same name, type and URL
+	AdoptionPolicyNameAndURL AdoptionPolicy = "name-and-url"
+)
+
+var adoptionPolicies = []AdoptionPolicy{AdoptionPolicyNone, AdoptionPolicyURL, AdoptionPolicyNameAndURL}
+
+// ParseAdoptionPolicy returns the given adoption policy, or an error when it's not supported
+func ParseAdoptionPolicy(s string) (AdoptionPolicy, error) {
+	policy := AdoptionPolicy(strings.ToLower(s))
+	if !slices.Contains(adoptionPolicies, policy) {
+		return AdoptionPolicyNone, fmt.Errorf("unsupported adoption policy '%s', should be one of %v", s, adoptionPolicies)
+	}
+	return policy, nil
+}
+
+// Matches whether the given (unmanaged) candidate check may be adopted for the given check according to this policy
+func (p AdoptionPolicy) Matches(check UptimeCheck, candidate UptimeCheck) bool {
+	switch p {
+	case AdoptionPolicyURL:
+		return check.Type == candidate.Type && sameURL(check.URL, candidate.URL)
+	case AdoptionPolicyNameAndURL:
+		return check.Name == candidate.Name && check.Type == candidate.Type && sameURL(check.URL, candidate.URL)
+	default:
+		return false
+	}
+}
+
+// sameURL whether the given URLs are equal, ignoring case of scheme and host and default ports
+func sameURL(a string, b string) bool {
+	urlA, errA := url.Parse(a)
+	urlB, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(urlA.Scheme, urlB.Scheme) &&
+		strings.EqualFold(urlA.Hostname(), urlB.Hostname()) &&
+		samePort(urlA, urlB) &&
+		strings.TrimPrefix(urlA.Path, "/") == strings.TrimPrefix(urlB.Path, "/") &&
+		urlA.RawQuery == urlB.RawQuery
+}
+
+// samePort whether the given URLs have the same port, either explicitly or by default
+func samePort(a *url.URL, b *url.URL) bool {
+	portA, _ := GetPort(a)
+	portB, _ := GetPort(b)
+	return portA == portB
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAdoptionPolicy(t *testing.T) {
+	policy, err := ParseAdoptionPolicy("Name-And-URL")
+	assert.NoError(t, err)
+	assert.Equal(t, AdoptionPolicyNameAndURL, policy)
+
+	_, err = ParseAdoptionPolicy("name")
+	assert.Error(t, err)
+}
+
+func TestAdoptionPolicy_Matches(t *testing.T) {
+	check := UptimeCheck{Name: "WMS", Type: CheckTypeHTTP, URL: "https://site.example/wms?service=WMS"}
+	tests := []struct {
+		name           string
+		candidate      UptimeCheck
+		wantURL        bool
+		wantNameAndURL bool
+	}{
+		{
+			name:           "Identical",
+			candidate:      check,
+			wantURL:        true,
+			wantNameAndURL: true,
+		},
+		{
+			name:           "Default port and case of host",
+			candidate:      UptimeCheck{Name: "WMS", Type: CheckTypeHTTP, URL: "https://Site.example:443/wms?service=WMS"},
+			wantURL:        true,
+			wantNameAndURL: true,
+		},
+		{
+			name:           "Other name",
+			candidate:      UptimeCheck{Name: "Other", Type: CheckTypeHTTP, URL: "https://site.example/wms?service=WMS"},
+			wantURL:        true,
+			wantNameAndURL: false,
+		},
+		{
+			name:      "Other query",
+			candidate: UptimeCheck{Name: "WMS", Type: CheckTypeHTTP, URL: "https://site.example/wms?service=WFS"},
+		},
+		{
+			name:      "Other scheme",
+			candidate: UptimeCheck{Name: "WMS", Type: CheckTypeHTTP, URL: "http://site.example/wms?service=WMS"},
+		},
+		{
+			name:      "Other port",
+			candidate: UptimeCheck{Name: "WMS", Type: CheckTypeHTTP, URL: "https://site.example:8443/wms?service=WMS"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.False(t, AdoptionPolicyNone.Matches(check, tt.candidate))
+			assert.Equal(t, tt.wantURL, AdoptionPolicyURL.Matches(check, tt.candidate))
+			assert.Equal(t, tt.wantNameAndURL, AdoptionPolicyNameAndURL.Matches(check, tt.candidate))
+		})
+	}
+}
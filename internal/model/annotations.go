@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnmanagedCheck uptime check at the uptime provider which isn't managed by the operator (yet),
+// e.g. because it was created manually before the operator was in use.
+type UnmanagedCheck struct {
+	// ProviderID ID of the check at the uptime provider
+	ProviderID string `json:"provider_id"`
+
+	// UptimeCheck settings of the check, as far as these can be expressed in annotations. Without ID.
+	UptimeCheck
+}
+
+// Annotations returns the annotations of an ingress route which result in this check, the inverse of
+// NewUptimeCheck. Settings which are equal to the defaults are omitted. Request headers and the request
+// body may contain secrets (e.g. an API key) which shouldn't end up in annotations, so these are omitted
+// too, see OmittedAnnotations.
+func (c UptimeCheck) Annotations() map[string]string {
+	result := map[string]string{
+		AnnotationID:   c.ID,
+		AnnotationName: c.Name,
+		AnnotationURL:  c.URL,
+	}
+	set := func(annotation string, value string) {
+		if value != "" {
+			result[annotation] = value
+		}
+	}
+	set(AnnotationTags, strings.Join(slices.DeleteFunc(slices.Clone(c.Tags), func(tag string) bool {
+		return tag == TagManagedBy
+	}), ","))
+	if c.Interval > 0 && c.Interval != defaultInterval {
+		set(AnnotationInterval, formatDuration(c.Interval))
+	}
+	if c.RequestBody == "" {
+		set(AnnotationHTTPMethod, c.HTTPMethod)
+	}
+	set(AnnotationStringContains, c.StringContains)
+	set(AnnotationStringNotContains, c.StringNotContains)
+	if c.TLSExpiryAlertDays > 0 {
+		set(AnnotationTLSExpiry, strconv.Itoa(c.TLSExpiryAlertDays))
+	}
+	set(AnnotationTCPStringToSend, c.TCPStringToSend)
+	set(AnnotationTCPStringToExpect, c.TCPStringToExpect)
+	set(AnnotationDNSExpectedIP, c.DNSExpectedIP)
+	set(AnnotationDNSNameserver, c.DNSNameserver)
+	set(AnnotationRegions, strings.Join(c.Regions, ","))
+	if c.ResponseTimeThreshold > 0 {
+		set(AnnotationResponseTimeThreshold, formatDuration(c.ResponseTimeThreshold))
+	}
+	if c.AlertAfterFailures > 0 {
+		set(AnnotationAlertAfterFailures, strconv.Itoa(c.AlertAfterFailures))
+	}
+	if c.RecoveryPeriod > 0 {
+		set(AnnotationRecoveryPeriod, formatDuration(c.RecoveryPeriod))
+	}
+	if c.Paused {
+		set(AnnotationPaused, "true")
+	}
+	return result
+}
+
+// OmittedAnnotations returns the annotations for settings of this check which Annotations omits since these
+// may contain secrets, to be added manually (e.g. referencing a Secret). Includes the names of the request headers.
+func (c UptimeCheck) OmittedAnnotations() []string {
+	var result []string
+	if len(c.RequestHeaders) > 0 {
+		result = append(result, fmt.Sprintf("%s (%s)", AnnotationRequestHeaders,
+			strings.Join(slices.Sorted(maps.Keys(c.RequestHeaders)), ", ")))
+	}
+	if c.RequestBody != "" {
+		result = append(result, AnnotationHTTPMethod, AnnotationRequestBody)
+	}
+	return result
+}
+
+// formatDuration formats the given duration without redundant zero units, e.g. "5m" instead of "5m0s"
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
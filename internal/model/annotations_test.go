@@ -0,0 +1,118 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUptimeCheck_Annotations(t *testing.T) {
+	tests := []struct {
+		name            string
+		check           UptimeCheck
+		wantAnnotations map[string]string
+	}{
+		{
+			name: "Minimal check",
+			check: UptimeCheck{
+				ID:       "abc",
+				Name:     "Minimal",
+				Type:     CheckTypeHTTP,
+				URL:      "https://site.example/path",
+				Interval: time.Minute,
+			},
+			wantAnnotations: map[string]string{
+				"uptime.pdok.nl/id":   "abc",
+				"uptime.pdok.nl/name": "Minimal",
+				"uptime.pdok.nl/url":  "https://site.example/path",
+			},
+		},
+		{
+			name: "HTTP check with all settings",
+			check: UptimeCheck{
+				ID:                    "def",
+				Name:                  "Everything",
+				Type:                  CheckTypeHTTP,
+				URL:                   "https://site.example/path?service=WMS",
+				Tags:                  []string{"tag1", "tag2", TagManagedBy},
+				Interval:              5 * time.Minute,
+				RequestHeaders:        map[string]string{"Accept": "text/xml, application/json"},
+				HTTPMethod:            "POST",
+				RequestBody:           "<Execute/>",
+				StringContains:        "OK",
+				TLSExpiryAlertDays:    14,
+				Regions:               []string{"eu", "na"},
+				ResponseTimeThreshold: 1500 * time.Millisecond,
+				AlertAfterFailures:    2,
+				RecoveryPeriod:        time.Hour,
+				Paused:                true,
+			},
+			wantAnnotations: map[string]string{
+				"uptime.pdok.nl/id":                                 "def",
+				"uptime.pdok.nl/name":                               "Everything",
+				"uptime.pdok.nl/url":                                "https://site.example/path?service=WMS",
+				"uptime.pdok.nl/tags":                               "tag1,tag2",
+				"uptime.pdok.nl/interval":                           "5m",
+				"uptime.pdok.nl/response-check-for-string-contains": "OK",
+				"uptime.pdok.nl/tls-expiry-alert-in-days":           "14",
+				"uptime.pdok.nl/regions":                            "eu,na",
+				"uptime.pdok.nl/response-time-threshold":            "1.5s",
+				"uptime.pdok.nl/alert-after-failures":               "2",
+				"uptime.pdok.nl/recovery-period":                    "1h",
+				"uptime.pdok.nl/paused":                             "true",
+			},
+		},
+		{
+			name: "TCP check",
+			check: UptimeCheck{
+				ID:                "ghi",
+				Name:              "Database",
+				Type:              CheckTypeTCP,
+				URL:               "tcp://host.example:5432",
+				Interval:          time.Minute,
+				TCPStringToExpect: "ready",
+			},
+			wantAnnotations: map[string]string{
+				"uptime.pdok.nl/id":                   "ghi",
+				"uptime.pdok.nl/name":                 "Database",
+				"uptime.pdok.nl/url":                  "tcp://host.example:5432",
+				"uptime.pdok.nl/tcp-string-to-expect": "ready",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := tt.check.Annotations()
+			assert.Equal(t, tt.wantAnnotations, annotations)
+
+			// annotations should result in the same check again
+			check, err := NewUptimeCheck(context.Background(), "route", annotations, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.check.Annotations(), check.Annotations())
+			assert.Contains(t, check.Tags, TagManagedBy)
+		})
+	}
+}
+
+func TestUptimeCheck_OmittedAnnotations(t *testing.T) {
+	check := UptimeCheck{
+		ID:             "def",
+		Name:           "Secrets",
+		URL:            "https://site.example/path",
+		RequestHeaders: map[string]string{"X-Api-Key": "s3cr3t", "Authorization": "Bearer s3cr3t"},
+		HTTPMethod:     "POST",
+		RequestBody:    "s3cr3t",
+	}
+	assert.Equal(t, []string{
+		"uptime.pdok.nl/request-headers (Authorization, X-Api-Key)",
+		"uptime.pdok.nl/http-method",
+		"uptime.pdok.nl/request-body",
+	}, check.OmittedAnnotations())
+	for _, value := range check.Annotations() {
+		assert.NotContains(t, value, "s3cr3t")
+	}
+	assert.Empty(t, UptimeCheck{HTTPMethod: "HEAD"}.OmittedAnnotations())
+}
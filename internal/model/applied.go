@@ -0,0 +1,157 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// LastApplied keeps track of the uptime checks of an ingress route as last applied to
+// the uptime provider. Stored as an annotation on the ingress route itself.
+type LastApplied struct {
+	IDs []string `json:"ids"`
+
+	// Hashes of the checks (by ID) as last successfully applied, see UptimeCheck.Hash
+	Hashes map[string]string `json:"hashes,omitempty"`
+
+	// Paused IDs of the checks which were paused when last successfully applied
+	Paused []string `json:"paused,omitempty"`
+
+	// Maintenance IDs of the checks which had a maintenance window registered with the provider when last successfully applied
+	Maintenance []string `json:"maintenance,omitempty"`
+}
+
+func NewLastApplied(checks []UptimeCheck) LastApplied {
+	result := LastApplied{}
+	for _, check := range checks {
+		result.IDs = append(result.IDs, check.ID)
+	}
+	slices.Sort(result.IDs)
+	return result
+}
+
+func GetLastApplied(annotations map[string]string) (LastApplied, error) {
+	result := LastApplied{}
+	value, ok := annotations[AnnotationLastApplied]
+	if !ok || value == "" {
+		return result, nil
+	}
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		return result, fmt.Errorf("%s annotation should contain valid JSON: %w", AnnotationLastApplied, err)
+	}
+	return result, nil
+}
+
+// Merge returns the union of both LastApplied states
+func (l LastApplied) Merge(other LastApplied) LastApplied {
+	result := LastApplied{IDs: slices.Clone(l.IDs), Hashes: maps.Clone(l.Hashes), Paused: slices.Clone(l.Paused),
+		Maintenance: slices.Clone(l.Maintenance)}
+	for _, id := range other.IDs {
+		if !slices.Contains(result.IDs, id) {
+			result.IDs = append(result.IDs, id)
+		}
+	}
+	for id, hash := range other.Hashes {
+		if _, ok := result.Hashes[id]; !ok && slices.Contains(result.IDs, id) {
+			if result.Hashes == nil {
+				result.Hashes = make(map[string]string)
+			}
+			result.Hashes[id] = hash
+			if other.WasPaused(id) {
+				result.Paused = append(result.Paused, id)
+			}
+			if other.HadMaintenanceWindow(id) {
+				result.Maintenance = append(result.Maintenance, id)
+			}
+		}
+	}
+	slices.Sort(result.IDs)
+	slices.Sort(result.Paused)
+	slices.Sort(result.Maintenance)
+	return result
+}
+
+// SetApplied records the given check, with its hash, as successfully applied
+func (l *LastApplied) SetApplied(check UptimeCheck, hash string) {
+	if l.Hashes == nil {
+		l.Hashes = make(map[string]string)
+	}
+	l.Hashes[check.ID] = hash
+	l.Paused = slices.DeleteFunc(l.Paused, func(id string) bool { return id == check.ID })
+	if check.Paused {
+		l.Paused = append(l.Paused, check.ID)
+		slices.Sort(l.Paused)
+	}
+	l.Maintenance = slices.DeleteFunc(l.Maintenance, func(id string) bool { return id == check.ID })
+	if check.Maintenance != nil {
+		l.Maintenance = append(l.Maintenance, check.ID)
+		slices.Sort(l.Maintenance)
+	}
+}
+
+// WasApplied whether the check with the given ID was successfully applied before
+func (l LastApplied) WasApplied(id string) bool {
+	_, ok := l.Hashes[id]
+	return ok
+}
+
+// WasPaused whether the check with the given ID was paused when last successfully applied
+func (l LastApplied) WasPaused(id string) bool {
+	return slices.Contains(l.Paused, id)
+}
+
+// HadMaintenanceWindow whether the check with the given ID had a maintenance window
+// registered with the provider when last successfully applied
+func (l LastApplied) HadMaintenanceWindow(id string) bool {
+	return slices.Contains(l.Maintenance, id)
+}
+
+// StaleIDs returns the IDs of checks which were applied before, but are no longer part of the given checks
+func (l LastApplied) StaleIDs(checks []UptimeCheck) []string {
+	var result []string
+	for _, id := range l.IDs {
+		if !slices.ContainsFunc(checks, func(check UptimeCheck) bool { return check.ID == id }) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func (l LastApplied) String() string {
+	value, _ := json.Marshal(l)
+	return string(value)
+}
+
+// Hash returns a digest of the check as applied to the uptime provider at the given time. Besides the
+// check itself this covers the (revealed) secret values and the current maintenance window occurrence,
+// so a change in a referenced Secret or the start of a new occurrence results in a different hash. The
+// digest is an HMAC with the given key, so the secret values can't be guessed from the (stored) hash.
+func (c UptimeCheck) Hash(now time.Time, key []byte) string {
+	state := struct {
+		Check          UptimeCheck       `json:"check"`
+		RequestHeaders map[string]string `json:"requestHeaders,omitempty"`
+		RequestBody    string            `json:"requestBody,omitempty"`
+		BasicAuth      []string          `json:"basicAuth,omitempty"`
+		From           time.Time         `json:"from"`
+		To             time.Time         `json:"to"`
+	}{
+		Check:          c,
+		RequestHeaders: c.RevealRequestHeaders(),
+		RequestBody:    c.RequestBody.Reveal(),
+	}
+	if c.BasicAuth != nil {
+		state.BasicAuth = []string{c.BasicAuth.Username, c.BasicAuth.Password.Reveal()}
+	}
+	if c.Maintenance != nil {
+		state.From, state.To, _ = c.Maintenance.Occurrence(now)
+	}
+	value, _ := json.Marshal(state)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(value)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUptimeCheck_Hash(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	check := UptimeCheck{
+		ID:          "id",
+		Name:        "name",
+		URL:         "https://pdok.example",
+		RequestBody: Secret("s3cr3t"),
+		Maintenance: &MaintenanceWindow{Cron: "0 2 * * *", Duration: time.Hour},
+	}
+	key := []byte("key")
+	hash := check.Hash(now, key)
+	assert.Len(t, hash, 64)
+	assert.NotEqual(t, hash, check.Hash(now, []byte("other key")), "hash should depend on the key")
+	assert.Equal(t, hash, check.Hash(now.Add(time.Hour), key), "same maintenance occurrence")
+
+	changedSecret := check
+	changedSecret.RequestBody = Secret("other")
+	assert.NotEqual(t, hash, changedSecret.Hash(now, key), "secret values should be part of the hash")
+
+	assert.NotEqual(t, hash, check.Hash(now.Add(24*time.Hour), key), "next maintenance occurrence")
+
+	changedTags := check
+	changedTags.Tags = []string{"tag"}
+	assert.NotEqual(t, hash, changedTags.Hash(now, key))
+}
+
+func TestLastApplied_Merge(t *testing.T) {
+	l := LastApplied{IDs: []string{"b"}, Hashes: map[string]string{"b": "new"}}
+	other := LastApplied{IDs: []string{"a", "b"}, Hashes: map[string]string{"a": "1", "b": "old"},
+		Paused: []string{"a", "b"}, Maintenance: []string{"a", "b"}}
+	assert.Equal(t, LastApplied{IDs: []string{"a", "b"}, Hashes: map[string]string{"a": "1", "b": "new"},
+		Paused: []string{"a"}, Maintenance: []string{"a"}}, l.Merge(other))
+}
+
+func TestLastApplied_SetApplied(t *testing.T) {
+	l := LastApplied{IDs: []string{"a"}}
+	assert.False(t, l.WasApplied("a"))
+
+	l.SetApplied(UptimeCheck{ID: "a", Paused: true, Maintenance: &MaintenanceWindow{}}, "1")
+	assert.True(t, l.WasApplied("a"))
+	assert.True(t, l.WasPaused("a"))
+	assert.True(t, l.HadMaintenanceWindow("a"))
+
+	l.SetApplied(UptimeCheck{ID: "a"}, "2")
+	assert.Equal(t, "2", l.Hashes["a"])
+	assert.False(t, l.WasPaused("a"))
+	assert.False(t, l.HadMaintenanceWindow("a"))
+}
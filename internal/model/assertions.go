@@ -0,0 +1,100 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// notContainsIDSuffix suffix of the ID of the additional check which holds the string-not-contains assertion
+const notContainsIDSuffix = "-not-contains"
+
+// JSONPathAssertion asserts that a JSON response contains the given path, and optionally
+// that the value at this path equals the given value.
+type JSONPathAssertion struct {
+	Path  string `json:"path"`
+	Value string `json:"value,omitempty"`
+}
+
+// responseAssertions assertions on the HTTP response, besides the plain (not-)contains strings
+type responseAssertions struct {
+	statusCodes []int
+	regex       string
+	jsonPath    *JSONPathAssertion
+}
+
+func getResponseAssertions(annotations map[string]string) (*responseAssertions, error) {
+	statusCodes, err := getExpectedStatusCodes(annotations)
+	if err != nil {
+		return nil, err
+	}
+	regex := annotations[AnnotationStringMatchesRegex]
+	if _, err = regexp.Compile(regex); err != nil {
+		return nil, fmt.Errorf("%s annotation should contain a valid regular expression: %w", AnnotationStringMatchesRegex, err)
+	}
+	jsonPath, err := getJSONPathAssertion(annotations)
+	if err != nil {
+		return nil, err
+	}
+	return &responseAssertions{
+		statusCodes: statusCodes,
+		regex:       regex,
+		jsonPath:    jsonPath,
+	}, nil
+}
+
+func getExpectedStatusCodes(annotations map[string]string) ([]int, error) {
+	var result []int
+	for _, value := range stringToSlice(annotations[AnnotationExpectedStatusCodes]) {
+		statusCode, err := strconv.Atoi(value)
+		if err != nil || statusCode < 100 || statusCode > 599 {
+			return nil, fmt.Errorf("%s annotation should contain a comma separated list of HTTP status codes, got '%s'",
+				AnnotationExpectedStatusCodes, value)
+		}
+		result = append(result, statusCode)
+	}
+	return result, nil
+}
+
+func getJSONPathAssertion(annotations map[string]string) (*JSONPathAssertion, error) {
+	path, hasPath := annotations[AnnotationJSONPath]
+	value, hasValue := annotations[AnnotationJSONPathValue]
+	if !hasPath {
+		if hasValue {
+			return nil, fmt.Errorf("%s annotation requires a %s annotation", AnnotationJSONPathValue, AnnotationJSONPath)
+		}
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "$") {
+		return nil, errors.New(AnnotationJSONPath + " annotation should contain a JSONPath expression starting with '$', e.g. $.status")
+	}
+	return &JSONPathAssertion{Path: path, Value: value}, nil
+}
+
+// ResponseAssertionAnnotations returns the annotations of the (non-string) response assertions
+// specified for this check, which not every uptime provider supports.
+func (c UptimeCheck) ResponseAssertionAnnotations() []string {
+	var result []string
+	if len(c.ExpectedStatusCodes) > 0 {
+		result = append(result, AnnotationExpectedStatusCodes)
+	}
+	if c.StringMatchesRegex != "" {
+		result = append(result, AnnotationStringMatchesRegex)
+	}
+	if c.JSONPath != nil {
+		result = append(result, AnnotationJSONPath)
+	}
+	return result
+}
+
+// NotContainsCheckID returns the ID of the additional check holding the string-not-contains assertion of this
+// check, for uptime providers which can't combine it with a string-contains assertion in one check. Returns false
+// when this check doesn't assert both.
+func (c UptimeCheck) NotContainsCheckID() (string, bool) {
+	if c.StringContains == "" || c.StringNotContains == "" {
+		return "", false
+	}
+	return c.ID + notContainsIDSuffix, true
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUptimeCheck_ResponseAssertions(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		wantStatusCodes []int
+		wantRegex       string
+		wantJSONPath    *JSONPathAssertion
+		wantErr         bool
+	}{
+		{
+			name: "All assertions",
+			annotations: map[string]string{
+				"uptime.pdok.nl/expected-status-codes":              "200, 204",
+				"uptime.pdok.nl/response-check-for-regex":           "version: \\d+",
+				"uptime.pdok.nl/response-check-for-json-path":       "$.status",
+				"uptime.pdok.nl/response-check-for-json-path-value": "ok",
+			},
+			wantStatusCodes: []int{200, 204},
+			wantRegex:       "version: \\d+",
+			wantJSONPath:    &JSONPathAssertion{Path: "$.status", Value: "ok"},
+		},
+		{
+			name:         "JSONPath without value",
+			annotations:  map[string]string{"uptime.pdok.nl/response-check-for-json-path": "$.links[0]"},
+			wantJSONPath: &JSONPathAssertion{Path: "$.links[0]"},
+		},
+		{
+			name:        "Invalid status code",
+			annotations: map[string]string{"uptime.pdok.nl/expected-status-codes": "200, 999"},
+			wantErr:     true,
+		},
+		{
+			name:        "Invalid regex",
+			annotations: map[string]string{"uptime.pdok.nl/response-check-for-regex": "(unclosed"},
+			wantErr:     true,
+		},
+		{
+			name:        "Invalid JSONPath",
+			annotations: map[string]string{"uptime.pdok.nl/response-check-for-json-path": "status"},
+			wantErr:     true,
+		},
+		{
+			name:        "JSONPath value without path",
+			annotations: map[string]string{"uptime.pdok.nl/response-check-for-json-path-value": "ok"},
+			wantErr:     true,
+		},
+		{
+			name: "Assertion on TCP check",
+			annotations: map[string]string{
+				"uptime.pdok.nl/url":                   "tcp://pdok.example:5432",
+				"uptime.pdok.nl/expected-status-codes": "200",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.annotations["uptime.pdok.nl/id"] = "1234567890"
+			tt.annotations["uptime.pdok.nl/name"] = "Test Check"
+			if _, ok := tt.annotations["uptime.pdok.nl/url"]; !ok {
+				tt.annotations["uptime.pdok.nl/url"] = "https://pdok.example"
+			}
+
+			check, err := NewUptimeCheck(context.TODO(), "test-ingress", tt.annotations, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatusCodes, check.ExpectedStatusCodes)
+			assert.Equal(t, tt.wantRegex, check.StringMatchesRegex)
+			assert.Equal(t, tt.wantJSONPath, check.JSONPath)
+		})
+	}
+}
+
+func TestUptimeCheck_NotContainsCheckID(t *testing.T) {
+	_, ok := UptimeCheck{ID: "id", StringContains: "OK"}.NotContainsCheckID()
+	assert.False(t, ok)
+
+	id, ok := UptimeCheck{ID: "id", StringContains: "OK", StringNotContains: "Exception"}.NotContainsCheckID()
+	assert.True(t, ok)
+	assert.Equal(t, "id-not-contains", id)
+}
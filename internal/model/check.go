@@ -1,10 +1,14 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -13,31 +17,161 @@ const (
 	// TagManagedBy Indicate to humans that the given check is managed by the operator.
 	TagManagedBy = "managed-by-" + OperatorName
 
-	AnnotationBase              = "uptime.pdok.nl"
-	AnnotationID                = AnnotationBase + "/id"
-	AnnotationName              = AnnotationBase + "/name"
-	AnnotationURL               = AnnotationBase + "/url"
-	AnnotationTags              = AnnotationBase + "/tags"
-	AnnotationInterval          = AnnotationBase + "/interval-in-minutes"
-	AnnotationRequestHeaders    = AnnotationBase + "/request-headers"
-	AnnotationStringContains    = AnnotationBase + "/response-check-for-string-contains"
-	AnnotationStringNotContains = AnnotationBase + "/response-check-for-string-not-contains"
-	AnnotationFinalizer         = AnnotationBase + "/finalizer"
-	AnnotationIgnore            = AnnotationBase + "/ignore"
+	defaultInterval = time.Minute
+
+	AnnotationBase                  = "uptime.pdok.nl"
+	AnnotationID                    = AnnotationBase + "/id"
+	AnnotationName                  = AnnotationBase + "/name"
+	AnnotationURL                   = AnnotationBase + "/url"
+	AnnotationTags                  = AnnotationBase + "/tags"
+	AnnotationInterval              = AnnotationBase + "/interval"
+	AnnotationIntervalInMinutes     = AnnotationBase + "/interval-in-minutes"
+	AnnotationRequestHeaders        = AnnotationBase + "/request-headers"
+	AnnotationStringContains        = AnnotationBase + "/response-check-for-string-contains"
+	AnnotationStringNotContains     = AnnotationBase + "/response-check-for-string-not-contains"
+	AnnotationTLSExpiry             = AnnotationBase + "/tls-expiry-alert-in-days"
+	AnnotationType                  = AnnotationBase + "/type"
+	AnnotationExpectedStatusCodes   = AnnotationBase + "/expected-status-codes"
+	AnnotationStringMatchesRegex    = AnnotationBase + "/response-check-for-regex"
+	AnnotationJSONPath              = AnnotationBase + "/response-check-for-json-path"
+	AnnotationJSONPathValue         = AnnotationBase + "/response-check-for-json-path-value"
+	AnnotationHTTPMethod            = AnnotationBase + "/http-method"
+	AnnotationRequestBody           = AnnotationBase + "/request-body"
+	AnnotationRequestBodyFrom       = AnnotationBase + "/request-body-from"
+	AnnotationBasicAuthSecret       = AnnotationBase + "/basic-auth-secret"
+	AnnotationTCPStringToSend       = AnnotationBase + "/tcp-string-to-send"
+	AnnotationTCPStringToExpect     = AnnotationBase + "/tcp-string-to-expect"
+	AnnotationDNSExpectedIP         = AnnotationBase + "/dns-expected-ip"
+	AnnotationDNSNameserver         = AnnotationBase + "/dns-nameserver"
+	AnnotationFinalizer             = AnnotationBase + "/finalizer"
+	AnnotationIgnore                = AnnotationBase + "/ignore"
+	AnnotationPaused                = AnnotationBase + "/paused"
+	AnnotationRegions               = AnnotationBase + "/regions"
+	AnnotationResponseTimeThreshold = AnnotationBase + "/response-time-threshold"
+	AnnotationAlertAfterFailures    = AnnotationBase + "/alert-after-failures"
+	AnnotationRecoveryPeriod        = AnnotationBase + "/recovery-period"
+	AnnotationLastApplied           = AnnotationBase + "/last-applied"
+
+	AnnotationMaintenanceCron     = AnnotationBase + "/maintenance-cron"
+	AnnotationMaintenanceDuration = AnnotationBase + "/maintenance-duration"
+	AnnotationMaintenanceStart    = AnnotationBase + "/maintenance-start"
+	AnnotationMaintenanceEnd      = AnnotationBase + "/maintenance-end"
 )
 
 type UptimeCheck struct {
-	ID                string            `json:"id"`
-	Name              string            `json:"name"`
-	URL               string            `json:"url"`
-	Tags              []string          `json:"tags"`
-	Interval          int               `json:"resolution"`
-	RequestHeaders    map[string]string `json:"request_headers"`
-	StringContains    string            `json:"string_contains"`
-	StringNotContains string            `json:"string_not_contains"`
+	ID                    string             `json:"id"`
+	Name                  string             `json:"name"`
+	Type                  CheckType          `json:"type"`
+	URL                   string             `json:"url"`
+	Tags                  []string           `json:"tags"`
+	Interval              time.Duration      `json:"interval"`
+	RequestHeaders        map[string]string  `json:"request_headers"`
+	SecretRequestHeaders  map[string]Secret  `json:"secret_request_headers,omitempty"`
+	HTTPMethod            string             `json:"http_method,omitempty"`
+	RequestBody           Secret             `json:"request_body,omitempty"`
+	BasicAuth             *BasicAuth         `json:"basic_auth,omitempty"`
+	StringContains        string             `json:"string_contains"`
+	StringNotContains     string             `json:"string_not_contains"`
+	ExpectedStatusCodes   []int              `json:"expected_status_codes,omitempty"`
+	StringMatchesRegex    string             `json:"string_matches_regex,omitempty"`
+	JSONPath              *JSONPathAssertion `json:"json_path,omitempty"`
+	TLSExpiryAlertDays    int                `json:"tls_expiry_alert_days,omitempty"`
+	TCPStringToSend       string             `json:"tcp_string_to_send,omitempty"`
+	TCPStringToExpect     string             `json:"tcp_string_to_expect,omitempty"`
+	DNSExpectedIP         string             `json:"dns_expected_ip,omitempty"`
+	DNSNameserver         string             `json:"dns_nameserver,omitempty"`
+	Regions               []string           `json:"regions,omitempty"`
+	ResponseTimeThreshold time.Duration      `json:"response_time_threshold,omitempty"`
+	AlertAfterFailures    int                `json:"alert_after_failures,omitempty"`
+	RecoveryPeriod        time.Duration      `json:"recovery_period,omitempty"`
+	Maintenance           *MaintenanceWindow `json:"maintenance,omitempty"`
+	Paused                bool               `json:"paused"`
+}
+
+// NewUptimeChecks creates all uptime checks for an ingress route. Besides the regular annotations,
+// an ingress route may contain named groups of annotations (like "uptime.pdok.nl/wms.url" and
+// "uptime.pdok.nl/wfs.url") resulting in an additional check per group. Checks are returned for all
+// valid groups, the returned error contains the problems encountered in other groups (if any).
+//
+// References to Secrets and ConfigMaps are resolved using the given resolver. When the resolver
+// is nil references are left unresolved, which is fine when only the ID of a check is needed.
+func NewUptimeChecks(ctx context.Context, ingressName string, annotations map[string]string, resolver Resolver) ([]UptimeCheck, error) {
+	groups := groupAnnotations(annotations)
+	groupNames := slices.Sorted(maps.Keys(groups))
+
+	var result []UptimeCheck
+	var errs []error
+	for _, groupName := range groupNames {
+		check, err := NewUptimeCheck(ctx, ingressName, groups[groupName], resolver)
+		if err != nil {
+			if groupName != "" {
+				err = fmt.Errorf("group '%s': %w", groupName, err)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		if slices.ContainsFunc(result, func(other UptimeCheck) bool { return other.ID == check.ID }) {
+			errs = append(errs, fmt.Errorf("duplicate %s '%s' on ingress route %s", AnnotationID, check.ID, ingressName))
+			continue
+		}
+		result = append(result, *check)
+	}
+	return result, errors.Join(errs...)
+}
+
+// HasUptimeChecks whether the given annotations of an ingress route specify one or more (possibly invalid) uptime checks
+func HasUptimeChecks(annotations map[string]string) bool {
+	for key := range annotations {
+		field, found := strings.CutPrefix(key, AnnotationBase+"/")
+		if !found {
+			continue
+		}
+		if _, groupField, isGroup := strings.Cut(field, "."); isGroup {
+			field = groupField
+		}
+		switch AnnotationBase + "/" + field {
+		case AnnotationID, AnnotationName, AnnotationURL:
+			return true
+		}
+	}
+	return false
+}
+
+// groupAnnotations splits uptime annotations into groups, where the group name is the
+// part before the first dot (e.g. "wms" in "uptime.pdok.nl/wms.url"). Keys within a group
+// are normalized to regular annotations (e.g. "uptime.pdok.nl/url"). Regular annotations
+// end up in the unnamed group, which is omitted when only named groups are used.
+func groupAnnotations(annotations map[string]string) map[string]map[string]string {
+	groups := make(map[string]map[string]string)
+	for key, value := range annotations {
+		field, found := strings.CutPrefix(key, AnnotationBase+"/")
+		if !found {
+			continue
+		}
+		groupName, groupField, isGroup := strings.Cut(field, ".")
+		if !isGroup {
+			groupName, groupField = "", field
+		}
+		if groups[groupName] == nil {
+			groups[groupName] = make(map[string]string)
+		}
+		groups[groupName][AnnotationBase+"/"+groupField] = value
+	}
+	if unnamed, ok := groups[""]; ok && len(groups) > 1 {
+		_, hasID := unnamed[AnnotationID]
+		_, hasName := unnamed[AnnotationName]
+		_, hasURL := unnamed[AnnotationURL]
+		if !hasID && !hasName && !hasURL {
+			delete(groups, "")
+		}
+	}
+	if len(groups) == 0 {
+		groups[""] = annotations // results in proper error messages about missing annotations
+	}
+	return groups
 }
 
-func NewUptimeCheck(ingressName string, annotations map[string]string) (*UptimeCheck, error) {
+func NewUptimeCheck(ctx context.Context, ingressName string, annotations map[string]string, resolver Resolver) (*UptimeCheck, error) {
 	id, ok := annotations[AnnotationID]
 	if !ok {
 		return nil, fmt.Errorf("%s annotation not found on ingress route: %s", AnnotationID, ingressName)
@@ -50,19 +184,69 @@ func NewUptimeCheck(ingressName string, annotations map[string]string) (*UptimeC
 	if !ok {
 		return nil, fmt.Errorf("%s annotation not found on ingress route %s", AnnotationURL, ingressName)
 	}
+	checkType, err := getCheckType(url, annotations)
+	if err != nil {
+		return nil, err
+	}
 	interval, err := getInterval(annotations)
 	if err != nil {
 		return nil, err
 	}
+	tlsExpiry, err := getTLSExpiry(url, annotations)
+	if err != nil {
+		return nil, err
+	}
+	paused, err := getPaused(annotations)
+	if err != nil {
+		return nil, err
+	}
+	maintenance, err := getMaintenanceWindow(annotations)
+	if err != nil {
+		return nil, err
+	}
+	assertions, err := getResponseAssertions(annotations)
+	if err != nil {
+		return nil, err
+	}
+	request, err := getHTTPRequest(ctx, annotations, resolver)
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := getThresholds(annotations)
+	if err != nil {
+		return nil, err
+	}
 	check := &UptimeCheck{
-		ID:                id,
-		Name:              name,
-		URL:               url,
-		Tags:              stringToSlice(annotations[AnnotationTags]),
-		Interval:          interval,
-		RequestHeaders:    kvStringToMap(annotations[AnnotationRequestHeaders]),
-		StringContains:    annotations[AnnotationStringContains],
-		StringNotContains: annotations[AnnotationStringNotContains],
+		ID:                    id,
+		Name:                  name,
+		Type:                  checkType,
+		URL:                   url,
+		Tags:                  stringToSlice(annotations[AnnotationTags]),
+		Regions:               stringToSlice(strings.ToLower(annotations[AnnotationRegions])),
+		ResponseTimeThreshold: thresholds.responseTime,
+		AlertAfterFailures:    thresholds.alertAfterFailures,
+		RecoveryPeriod:        thresholds.recoveryPeriod,
+		Interval:              interval,
+		RequestHeaders:        request.headers,
+		SecretRequestHeaders:  request.secretHeaders,
+		StringContains:        annotations[AnnotationStringContains],
+		StringNotContains:     annotations[AnnotationStringNotContains],
+		HTTPMethod:            request.method,
+		RequestBody:           request.body,
+		BasicAuth:             request.basicAuth,
+		ExpectedStatusCodes:   assertions.statusCodes,
+		StringMatchesRegex:    assertions.regex,
+		JSONPath:              assertions.jsonPath,
+		Maintenance:           maintenance,
+		Paused:                paused,
+		TLSExpiryAlertDays:    tlsExpiry,
+		TCPStringToSend:       annotations[AnnotationTCPStringToSend],
+		TCPStringToExpect:     annotations[AnnotationTCPStringToExpect],
+		DNSExpectedIP:         annotations[AnnotationDNSExpectedIP],
+		DNSNameserver:         annotations[AnnotationDNSNameserver],
+	}
+	if err = validateCheckType(check); err != nil {
+		return nil, err
 	}
 	if !slices.Contains(check.Tags, TagManagedBy) {
 		check.Tags = append(check.Tags, TagManagedBy)
@@ -70,33 +254,57 @@ func NewUptimeCheck(ingressName string, annotations map[string]string) (*UptimeC
 	return check, nil
 }
 
-func getInterval(annotations map[string]string) (int, error) {
-	if _, ok := annotations[AnnotationInterval]; ok {
-		interval, err := strconv.Atoi(annotations[AnnotationInterval])
+// getInterval returns the interval between checks, which is either specified as duration (e.g. "30s")
+// or, for backwards compatibility, as number of minutes.
+func getInterval(annotations map[string]string) (time.Duration, error) {
+	interval, hasInterval := annotations[AnnotationInterval]
+	minutes, hasMinutes := annotations[AnnotationIntervalInMinutes]
+	switch {
+	case hasInterval && hasMinutes:
+		return defaultInterval, fmt.Errorf("either specify %s or %s, not both", AnnotationInterval, AnnotationIntervalInMinutes)
+	case hasInterval:
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			return defaultInterval, fmt.Errorf("%s annotation should contain a duration (e.g. 30s or 5m): %w", AnnotationInterval, err)
+		}
+		if d <= 0 {
+			return defaultInterval, errors.New(AnnotationInterval + " annotation should contain a positive duration")
+		}
+		return d, nil
+	case hasMinutes:
+		n, err := strconv.Atoi(minutes)
 		if err != nil {
-			return 1, fmt.Errorf("%s annotation should contain integer value: %w", AnnotationInterval, err)
+			return defaultInterval, fmt.Errorf("%s annotation should contain integer value: %w", AnnotationIntervalInMinutes, err)
 		}
-		return interval, nil
+		return time.Duration(n) * time.Minute, nil
+	default:
+		return defaultInterval, nil
 	}
-	return 1, nil
 }
 
-func kvStringToMap(s string) map[string]string {
-	if s == "" {
-		return nil
+func getTLSExpiry(url string, annotations map[string]string) (int, error) {
+	if _, ok := annotations[AnnotationTLSExpiry]; !ok {
+		return 0, nil
 	}
-	result := make(map[string]string)
-	kvPairs := strings.Split(s, ",")
-	for _, kvPair := range kvPairs {
-		parts := strings.Split(kvPair, ":")
-		if len(parts) != 2 {
-			continue
+	days, err := strconv.Atoi(annotations[AnnotationTLSExpiry])
+	if err != nil || days < 1 {
+		return 0, fmt.Errorf("%s annotation should contain a positive integer value", AnnotationTLSExpiry)
+	}
+	if !strings.HasPrefix(url, "https://") {
+		return 0, fmt.Errorf("%s annotation is only applicable to HTTPS URLs, got %s", AnnotationTLSExpiry, url)
+	}
+	return days, nil
+}
+
+func getPaused(annotations map[string]string) (bool, error) {
+	if _, ok := annotations[AnnotationPaused]; ok {
+		paused, err := strconv.ParseBool(annotations[AnnotationPaused])
+		if err != nil {
+			return false, fmt.Errorf("%s annotation should contain boolean value: %w", AnnotationPaused, err)
 		}
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		result[key] = value
+		return paused, nil
 	}
-	return result
+	return false, nil
 }
 
 func stringToSlice(s string) []string {
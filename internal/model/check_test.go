@@ -1,7 +1,11 @@
 package model
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewUptimeCheck(t *testing.T) {
@@ -90,13 +94,181 @@ func TestNewUptimeCheck(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:        "Paused annotation",
+			ingressName: "test-ingress",
+			annotations: map[string]string{
+				"uptime.pdok.nl/id":     "1234567890",
+				"uptime.pdok.nl/name":   "Test Check",
+				"uptime.pdok.nl/url":    "https://pdok.example",
+				"uptime.pdok.nl/paused": "true",
+			},
+			wantErr: false,
+		},
+		{
+			name:        "Invalid paused annotation",
+			ingressName: "test-ingress",
+			annotations: map[string]string{
+				"uptime.pdok.nl/id":     "1234567890",
+				"uptime.pdok.nl/name":   "Test Check",
+				"uptime.pdok.nl/url":    "https://pdok.example",
+				"uptime.pdok.nl/paused": "maybe",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := NewUptimeCheck(tt.ingressName, tt.annotations)
+			_, err := NewUptimeCheck(context.TODO(), tt.ingressName, tt.annotations, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewUptimeCheck() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestNewUptimeChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantIDs     []string
+		wantErr     bool
+	}{
+		{
+			name: "Only regular annotations",
+			annotations: map[string]string{
+				"uptime.pdok.nl/id":   "1234567890",
+				"uptime.pdok.nl/name": "Test Check",
+				"uptime.pdok.nl/url":  "https://pdok.example",
+			},
+			wantIDs: []string{"1234567890"},
+		},
+		{
+			name: "Only named groups",
+			annotations: map[string]string{
+				"uptime.pdok.nl/wms.id":   "wms-id",
+				"uptime.pdok.nl/wms.name": "WMS Check",
+				"uptime.pdok.nl/wms.url":  "https://pdok.example/wms",
+				"uptime.pdok.nl/wfs.id":   "wfs-id",
+				"uptime.pdok.nl/wfs.name": "WFS Check",
+				"uptime.pdok.nl/wfs.url":  "https://pdok.example/wfs",
+				"uptime.pdok.nl/ignore":   "",
+				"some.other/annotation":   "foo",
+			},
+			wantIDs: []string{"wfs-id", "wms-id"},
+		},
+		{
+			name: "Regular annotations and named group",
+			annotations: map[string]string{
+				"uptime.pdok.nl/id":       "1234567890",
+				"uptime.pdok.nl/name":     "Test Check",
+				"uptime.pdok.nl/url":      "https://pdok.example",
+				"uptime.pdok.nl/wms.id":   "wms-id",
+				"uptime.pdok.nl/wms.name": "WMS Check",
+				"uptime.pdok.nl/wms.url":  "https://pdok.example/wms",
+			},
+			wantIDs: []string{"1234567890", "wms-id"},
+		},
+		{
+			name: "Invalid named group doesn't affect other groups",
+			annotations: map[string]string{
+				"uptime.pdok.nl/wms.id":   "wms-id",
+				"uptime.pdok.nl/wms.name": "WMS Check",
+				"uptime.pdok.nl/wms.url":  "https://pdok.example/wms",
+				"uptime.pdok.nl/wfs.id":   "wfs-id",
+				"uptime.pdok.nl/wfs.name": "WFS Check",
+			},
+			wantIDs: []string{"wms-id"},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate IDs",
+			annotations: map[string]string{
+				"uptime.pdok.nl/wms.id":   "same-id",
+				"uptime.pdok.nl/wms.name": "WMS Check",
+				"uptime.pdok.nl/wms.url":  "https://pdok.example/wms",
+				"uptime.pdok.nl/wfs.id":   "same-id",
+				"uptime.pdok.nl/wfs.name": "WFS Check",
+				"uptime.pdok.nl/wfs.url":  "https://pdok.example/wfs",
+			},
+			wantIDs: []string{"same-id"},
+			wantErr: true,
+		},
+		{
+			name:        "No annotations",
+			annotations: map[string]string{},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, err := NewUptimeChecks(context.TODO(), "test-ingress", tt.annotations, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewUptimeChecks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var ids []string
+			for _, check := range checks {
+				ids = append(ids, check.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestGetInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "Default",
+			annotations: map[string]string{},
+			want:        time.Minute,
+		},
+		{
+			name:        "Duration",
+			annotations: map[string]string{"uptime.pdok.nl/interval": "30s"},
+			want:        30 * time.Second,
+		},
+		{
+			name:        "Minutes",
+			annotations: map[string]string{"uptime.pdok.nl/interval-in-minutes": "5"},
+			want:        5 * time.Minute,
+		},
+		{
+			name:        "Both",
+			annotations: map[string]string{"uptime.pdok.nl/interval": "5m", "uptime.pdok.nl/interval-in-minutes": "5"},
+			wantErr:     true,
+		},
+		{
+			name:        "Invalid duration",
+			annotations: map[string]string{"uptime.pdok.nl/interval": "5"},
+			wantErr:     true,
+		},
+		{
+			name:        "Negative duration",
+			annotations: map[string]string{"uptime.pdok.nl/interval": "-5m"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getInterval(tt.annotations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHasUptimeChecks(t *testing.T) {
+	assert.False(t, HasUptimeChecks(nil))
+	assert.False(t, HasUptimeChecks(map[string]string{"other": "annotation", AnnotationLastApplied: `{"ids":["id"]}`}))
+	assert.True(t, HasUptimeChecks(map[string]string{AnnotationID: "id"}))
+	assert.True(t, HasUptimeChecks(map[string]string{AnnotationBase + "/wms.url": "https://pdok.example"}))
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// CheckDefaults operator-wide defaults, for settings which aren't specified on the ingress route
+type CheckDefaults struct {
+	Regions               []string
+	ResponseTimeThreshold time.Duration
+	AlertAfterFailures    int
+	RecoveryPeriod        time.Duration
+}
+
+// ApplyDefaults applies the given defaults to the settings which aren't specified for this check
+func (c *UptimeCheck) ApplyDefaults(defaults CheckDefaults) {
+	if len(c.Regions) == 0 {
+		c.Regions = defaults.Regions
+	}
+	if c.ResponseTimeThreshold == 0 {
+		c.ResponseTimeThreshold = defaults.ResponseTimeThreshold
+	}
+	if c.AlertAfterFailures == 0 {
+		c.AlertAfterFailures = defaults.AlertAfterFailures
+	}
+	if c.RecoveryPeriod == 0 {
+		c.RecoveryPeriod = defaults.RecoveryPeriod
+	}
+}
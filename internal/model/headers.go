@@ -0,0 +1,122 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// parseRequestHeaders parses request headers formatted as comma separated "Name: value" pairs. Values
+// are split from names on the first colon, so values may contain colons. A value containing a comma (or
+// leading/trailing whitespace) should be double-quoted, in which case \" and \\ can be used as escapes.
+// Alternatively the headers can be specified as a JSON object, like {"Name": "value"}.
+func parseRequestHeaders(s string) (map[string]string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "{") {
+		return parseJSONRequestHeaders(s)
+	}
+	result := make(map[string]string)
+	rest := s
+	for rest != "" {
+		var name, value string
+		var err error
+		name, value, rest, err = nextRequestHeader(rest)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" && value == "" {
+			continue // tolerate empty entries, like a trailing comma
+		}
+		if err = addRequestHeader(result, name, value); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func parseJSONRequestHeaders(s string) (map[string]string, error) {
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(s), &headers); err != nil {
+		return nil, fmt.Errorf("invalid JSON object with request headers: %w", err)
+	}
+	result := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if err := addRequestHeader(result, strings.TrimSpace(name), value); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// nextRequestHeader parses the first "Name: value" pair of s, returns the remainder after the separating comma
+func nextRequestHeader(s string) (name string, value string, rest string, err error) {
+	nameEnd := strings.IndexAny(s, ":,")
+	if nameEnd < 0 || s[nameEnd] == ',' {
+		entry, remainder, _ := strings.Cut(s, ",")
+		if strings.TrimSpace(entry) == "" {
+			return "", "", remainder, nil
+		}
+		return "", "", "", fmt.Errorf("request header '%s' should be formatted as 'Name: value'", strings.TrimSpace(entry))
+	}
+	name = strings.TrimSpace(s[:nameEnd])
+	s = strings.TrimLeft(s[nameEnd+1:], " \t")
+	if !strings.HasPrefix(s, `"`) {
+		value, rest, _ = strings.Cut(s, ",")
+		return name, strings.TrimSpace(value), rest, nil
+	}
+
+	// quoted value
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 == len(s) {
+				return "", "", "", fmt.Errorf("value of request header '%s' ends with an incomplete escape", name)
+			}
+			i++
+			sb.WriteByte(s[i])
+		case '"':
+			remainder := strings.TrimLeft(s[i+1:], " \t")
+			if remainder != "" && remainder[0] != ',' {
+				return "", "", "", fmt.Errorf("unexpected '%s' after quoted value of request header '%s'", remainder, name)
+			}
+			return name, sb.String(), strings.TrimPrefix(remainder, ","), nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", "", "", fmt.Errorf("quoted value of request header '%s' isn't terminated", name)
+}
+
+func addRequestHeader(headers map[string]string, name string, value string) error {
+	if !isValidHeaderName(name) {
+		return fmt.Errorf("invalid request header name '%s'", name)
+	}
+	if strings.ContainsAny(value, "\r\n\x00") {
+		return fmt.Errorf("value of request header '%s' contains invalid characters", name)
+	}
+	for existing := range headers {
+		if strings.EqualFold(existing, name) {
+			return fmt.Errorf("request header '%s' is specified more than once", name)
+		}
+	}
+	headers[name] = value
+	return nil
+}
+
+// isValidHeaderName whether the name is a valid token, as defined in RFC 9110
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		isAlphaNumeric := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlphaNumeric && !strings.ContainsRune("!#$%&'*+-.^_`|~", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRequestHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "Empty",
+			s:    " ",
+			want: nil,
+		},
+		{
+			name: "Plain headers",
+			s:    "key1:value1, key2: value2 ,",
+			want: map[string]string{"key1": "value1", "key2": "value2"},
+		},
+		{
+			name: "Colon in value",
+			s:    "Authorization: Bearer a:b, Accept: text/xml",
+			want: map[string]string{"Authorization": "Bearer a:b", "Accept": "text/xml"},
+		},
+		{
+			name: "Quoted value with comma and escapes",
+			s:    `Accept: "text/xml, application/json", X-Quote: "say \"hi\" \\o/" , X-Space: " padded "`,
+			want: map[string]string{"Accept": "text/xml, application/json", "X-Quote": `say "hi" \o/`, "X-Space": " padded "},
+		},
+		{
+			name: "JSON",
+			s:    `{"Accept": "text/xml, application/json", "Authorization": "Bearer a:b"}`,
+			want: map[string]string{"Accept": "text/xml, application/json", "Authorization": "Bearer a:b"},
+		},
+		{
+			name:    "Missing colon",
+			s:       "key1:value1, key2",
+			wantErr: true,
+		},
+		{
+			name:    "Empty name",
+			s:       ": value",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid name",
+			s:       "X Api Key: value",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated quote",
+			s:       `Accept: "text/xml`,
+			wantErr: true,
+		},
+		{
+			name:    "Text after quoted value",
+			s:       `Accept: "text/xml" json`,
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate header",
+			s:       "Accept: text/xml, accept: application/json",
+			wantErr: true,
+		},
+		{
+			name:    "Newline in value",
+			s:       "{\"Accept\": \"text/xml\\r\\nX-Injected: true\"}",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			s:       `{"Accept": 1}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRequestHeaders(tt.s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
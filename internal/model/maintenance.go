@@ -0,0 +1,120 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// MaintenanceWindow period(s) during which an uptime check shouldn't alert. Either a
+// recurring window (cron expression + duration) or a one-off window (explicit start + end).
+type MaintenanceWindow struct {
+	Cron     string        `json:"cron,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Start    time.Time     `json:"start,omitempty"`
+	End      time.Time     `json:"end,omitempty"`
+}
+
+func (w MaintenanceWindow) IsRecurring() bool {
+	return w.Cron != ""
+}
+
+// Occurrence returns the maintenance window occurrence which is active at the given time, or
+// otherwise the next upcoming occurrence. Returns false when there's no active or upcoming occurrence.
+// Cron expressions are evaluated in UTC (unless prefixed with CRON_TZ), regardless of the local time zone.
+func (w MaintenanceWindow) Occurrence(now time.Time) (from time.Time, to time.Time, ok bool) {
+	now = now.UTC()
+	if !w.IsRecurring() {
+		if now.Before(w.End) {
+			return w.Start, w.End, true
+		}
+		return time.Time{}, time.Time{}, false
+	}
+	schedule, err := cron.ParseStandard(w.Cron)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	// first occurrence which hasn't ended yet
+	from = schedule.Next(now.Add(-w.Duration))
+	if from.IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, from.Add(w.Duration), true
+}
+
+// IsActive whether the given time falls within the maintenance window
+func (w MaintenanceWindow) IsActive(now time.Time) bool {
+	from, _, ok := w.Occurrence(now)
+	return ok && !now.Before(from)
+}
+
+// NextTransition returns the moment the maintenance window starts or ends, whichever
+// comes first after the given time. Returns false when there's no such moment.
+func (w MaintenanceWindow) NextTransition(now time.Time) (time.Time, bool) {
+	from, to, ok := w.Occurrence(now)
+	if !ok {
+		return time.Time{}, false
+	}
+	if now.Before(from) {
+		return from, true
+	}
+	return to, true
+}
+
+func getMaintenanceWindow(annotations map[string]string) (*MaintenanceWindow, error) {
+	cronExpr, hasCron := annotations[AnnotationMaintenanceCron]
+	duration, hasDuration := annotations[AnnotationMaintenanceDuration]
+	start, hasStart := annotations[AnnotationMaintenanceStart]
+	end, hasEnd := annotations[AnnotationMaintenanceEnd]
+
+	switch {
+	case !hasCron && !hasDuration && !hasStart && !hasEnd:
+		return nil, nil
+	case (hasCron || hasDuration) && (hasStart || hasEnd):
+		return nil, fmt.Errorf("either specify %s + %s or %s + %s, not both",
+			AnnotationMaintenanceCron, AnnotationMaintenanceDuration, AnnotationMaintenanceStart, AnnotationMaintenanceEnd)
+	case hasCron || hasDuration:
+		return getRecurringMaintenanceWindow(cronExpr, duration)
+	default:
+		return getOneOffMaintenanceWindow(start, end)
+	}
+}
+
+func getRecurringMaintenanceWindow(cronExpr string, duration string) (*MaintenanceWindow, error) {
+	if cronExpr == "" || duration == "" {
+		return nil, fmt.Errorf("both %s and %s annotations are required for a recurring maintenance window",
+			AnnotationMaintenanceCron, AnnotationMaintenanceDuration)
+	}
+	if _, err := cron.ParseStandard(cronExpr); err != nil {
+		return nil, fmt.Errorf("%s annotation should contain a valid cron expression: %w", AnnotationMaintenanceCron, err)
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("%s annotation should contain a duration (e.g. 1h30m): %w", AnnotationMaintenanceDuration, err)
+	}
+	if d <= 0 {
+		return nil, errors.New(AnnotationMaintenanceDuration + " annotation should contain a positive duration")
+	}
+	return &MaintenanceWindow{Cron: cronExpr, Duration: d}, nil
+}
+
+func getOneOffMaintenanceWindow(start string, end string) (*MaintenanceWindow, error) {
+	if start == "" || end == "" {
+		return nil, fmt.Errorf("both %s and %s annotations are required for a one-off maintenance window",
+			AnnotationMaintenanceStart, AnnotationMaintenanceEnd)
+	}
+	s, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return nil, fmt.Errorf("%s annotation should contain a RFC3339 timestamp: %w", AnnotationMaintenanceStart, err)
+	}
+	e, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return nil, fmt.Errorf("%s annotation should contain a RFC3339 timestamp: %w", AnnotationMaintenanceEnd, err)
+	}
+	if !e.After(s) {
+		return nil, fmt.Errorf("%s should be after %s", AnnotationMaintenanceEnd, AnnotationMaintenanceStart)
+	}
+	return &MaintenanceWindow{Start: s, End: e}, nil
+}
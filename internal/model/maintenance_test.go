@@ -0,0 +1,156 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceWindow_Occurrence(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC) // a monday
+	tests := []struct {
+		name       string
+		window     MaintenanceWindow
+		wantFrom   time.Time
+		wantTo     time.Time
+		wantOK     bool
+		wantActive bool
+	}{
+		{
+			name:     "Recurring, upcoming",
+			window:   MaintenanceWindow{Cron: "0 14 * * *", Duration: time.Hour},
+			wantFrom: time.Date(2024, 6, 3, 14, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2024, 6, 3, 15, 0, 0, 0, time.UTC),
+			wantOK:   true,
+		},
+		{
+			name:       "Recurring, active",
+			window:     MaintenanceWindow{Cron: "30 11 * * 1", Duration: time.Hour},
+			wantFrom:   time.Date(2024, 6, 3, 11, 30, 0, 0, time.UTC),
+			wantTo:     time.Date(2024, 6, 3, 12, 30, 0, 0, time.UTC),
+			wantOK:     true,
+			wantActive: true,
+		},
+		{
+			name:     "Recurring, just ended",
+			window:   MaintenanceWindow{Cron: "0 11 * * *", Duration: time.Hour},
+			wantFrom: time.Date(2024, 6, 4, 11, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC),
+			wantOK:   true,
+		},
+		{
+			name: "One-off, active",
+			window: MaintenanceWindow{
+				Start: time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 6, 3, 13, 0, 0, 0, time.UTC),
+			},
+			wantFrom:   time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC),
+			wantTo:     time.Date(2024, 6, 3, 13, 0, 0, 0, time.UTC),
+			wantOK:     true,
+			wantActive: true,
+		},
+		{
+			name: "One-off, in the past",
+			window: MaintenanceWindow{
+				Start: time.Date(2024, 6, 2, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 6, 2, 13, 0, 0, 0, time.UTC),
+			},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, ok := tt.window.Occurrence(now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantFrom, from)
+			assert.Equal(t, tt.wantTo, to)
+			assert.Equal(t, tt.wantActive, tt.window.IsActive(now))
+		})
+	}
+}
+
+func TestMaintenanceWindow_Occurrence_EvaluatesCronInUTC(t *testing.T) {
+	amsterdam := time.FixedZone("CEST", 2*60*60)
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, amsterdam) // 10:00 UTC
+	window := MaintenanceWindow{Cron: "0 11 * * *", Duration: time.Hour}
+
+	from, to, ok := window.Occurrence(now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 6, 3, 11, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC), to)
+	assert.False(t, window.IsActive(now))
+}
+
+func TestGetMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *MaintenanceWindow
+		wantErr     bool
+	}{
+		{
+			name:        "No maintenance window",
+			annotations: map[string]string{},
+		},
+		{
+			name: "Recurring",
+			annotations: map[string]string{
+				AnnotationMaintenanceCron:     "0 2 * * 0",
+				AnnotationMaintenanceDuration: "1h30m",
+			},
+			want: &MaintenanceWindow{Cron: "0 2 * * 0", Duration: 90 * time.Minute},
+		},
+		{
+			name: "One-off",
+			annotations: map[string]string{
+				AnnotationMaintenanceStart: "2024-06-03T10:00:00Z",
+				AnnotationMaintenanceEnd:   "2024-06-03T12:00:00Z",
+			},
+			want: &MaintenanceWindow{
+				Start: time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "Recurring without duration",
+			annotations: map[string]string{AnnotationMaintenanceCron: "0 2 * * 0"},
+			wantErr:     true,
+		},
+		{
+			name: "Invalid cron",
+			annotations: map[string]string{
+				AnnotationMaintenanceCron:     "every sunday",
+				AnnotationMaintenanceDuration: "1h",
+			},
+			wantErr: true,
+		},
+		{
+			name: "End before start",
+			annotations: map[string]string{
+				AnnotationMaintenanceStart: "2024-06-03T12:00:00Z",
+				AnnotationMaintenanceEnd:   "2024-06-03T10:00:00Z",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Both recurring and one-off",
+			annotations: map[string]string{
+				AnnotationMaintenanceCron:     "0 2 * * 0",
+				AnnotationMaintenanceDuration: "1h",
+				AnnotationMaintenanceStart:    "2024-06-03T10:00:00Z",
+				AnnotationMaintenanceEnd:      "2024-06-03T12:00:00Z",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getMaintenanceWindow(tt.annotations)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getMaintenanceWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ReferenceKind kind of Kubernetes resource a reference points to
+type ReferenceKind string
+
+const (
+	ReferenceSecret    ReferenceKind = "secret"
+	ReferenceConfigMap ReferenceKind = "configmap"
+)
+
+// Reference to a key in a Secret or ConfigMap, formatted as "secret:[namespace/]name/key"
+// or "configmap:[namespace/]name/key". The namespace defaults to that of the ingress route.
+type Reference struct {
+	Kind      ReferenceKind
+	Namespace string
+	Name      string
+	Key       string
+}
+
+// Resolver resolves references to the values stored in Secrets and ConfigMaps
+type Resolver interface {
+	Resolve(ctx context.Context, ref Reference) (string, error)
+}
+
+// SecretPlaceholder shown instead of the actual value of a Secret
+const SecretPlaceholder = "***"
+
+// Secret value which is never revealed in logs or (JSON) output, use Reveal to get the actual value
+type Secret string
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return SecretPlaceholder
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+func isSecretReference(s string) bool {
+	return strings.HasPrefix(s, string(ReferenceSecret)+":")
+}
+
+// References returns the references to Secrets and ConfigMaps in the given annotations, of all
+// groups. Used to revisit an ingress route when a referenced Secret or ConfigMap changes.
+func References(annotations map[string]string) []Reference {
+	var result []Reference
+	add := func(s string) {
+		if ref, err := ParseReference(s); err == nil {
+			result = append(result, *ref)
+		}
+	}
+	for _, group := range groupAnnotations(annotations) {
+		if secretName, ok := group[AnnotationBasicAuthSecret]; ok {
+			add(string(ReferenceSecret) + ":" + secretName + "/username")
+		}
+		if bodyRef, ok := group[AnnotationRequestBodyFrom]; ok {
+			add(bodyRef)
+		}
+		headers, _ := parseRequestHeaders(group[AnnotationRequestHeaders])
+		for _, value := range headers {
+			if isSecretReference(value) {
+				add(value)
+			}
+		}
+	}
+	return result
+}
+
+func ParseReference(s string) (*Reference, error) {
+	kind, location, ok := strings.Cut(s, ":")
+	if !ok || (ReferenceKind(kind) != ReferenceSecret && ReferenceKind(kind) != ReferenceConfigMap) {
+		return nil, fmt.Errorf("invalid reference '%s', expected secret:[namespace/]name/key or configmap:[namespace/]name/key", s)
+	}
+	ref := &Reference{Kind: ReferenceKind(kind)}
+	parts := strings.Split(location, "/")
+	switch len(parts) {
+	case 2:
+		ref.Name, ref.Key = parts[0], parts[1]
+	case 3:
+		ref.Namespace, ref.Name, ref.Key = parts[0], parts[1], parts[2]
+	default:
+		return nil, fmt.Errorf("invalid reference '%s', expected %s:[namespace/]name/key", s, kind)
+	}
+	if ref.Name == "" || ref.Key == "" {
+		return nil, fmt.Errorf("invalid reference '%s', both name and key are required", s)
+	}
+	return ref, nil
+}
+
+func (r Reference) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s:%s/%s", r.Kind, r.Name, r.Key)
+	}
+	return fmt.Sprintf("%s:%s/%s/%s", r.Kind, r.Namespace, r.Name, r.Key)
+}
+
+// resolve resolves the given reference, returns false when references can't be resolved
+// since no resolver is available (for example when deleting a check).
+func resolve(ctx context.Context, resolver Resolver, s string) (string, bool, error) {
+	if resolver == nil {
+		return "", false, nil
+	}
+	ref, err := ParseReference(s)
+	if err != nil {
+		return "", false, err
+	}
+	value, err := resolver.Resolve(ctx, *ref)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return value, true, nil
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    *Reference
+		wantErr bool
+	}{
+		{
+			name: "Secret",
+			s:    "secret:api-keys/pdok",
+			want: &Reference{Kind: ReferenceSecret, Name: "api-keys", Key: "pdok"},
+		},
+		{
+			name: "ConfigMap with namespace",
+			s:    "configmap:default/requests/body.xml",
+			want: &Reference{Kind: ReferenceConfigMap, Namespace: "default", Name: "requests", Key: "body.xml"},
+		},
+		{
+			name:    "Unknown kind",
+			s:       "pod:foo/bar",
+			wantErr: true,
+		},
+		{
+			name:    "Missing key",
+			s:       "secret:api-keys",
+			wantErr: true,
+		},
+		{
+			name:    "Empty name",
+			s:       "secret:/pdok",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReference(tt.s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.s, got.String())
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	annotations := map[string]string{
+		"uptime.pdok.nl/wms.request-headers":   "Accept: text/xml, X-Api-Key: secret:api-keys/wms",
+		"uptime.pdok.nl/wfs.basic-auth-secret": "credentials",
+		"uptime.pdok.nl/wfs.request-body-from": "configmap:requests/wfs",
+	}
+	assert.ElementsMatch(t, []Reference{
+		{Kind: ReferenceSecret, Name: "api-keys", Key: "wms"},
+		{Kind: ReferenceSecret, Name: "credentials", Key: "username"},
+		{Kind: ReferenceConfigMap, Name: "requests", Key: "wfs"},
+	}, References(annotations))
+}
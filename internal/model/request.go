@@ -0,0 +1,179 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var httpMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// BasicAuth credentials used by the uptime provider to access the URL of a check
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password Secret `json:"password"`
+}
+
+// httpRequest how the uptime provider should request the URL of a HTTP check
+type httpRequest struct {
+	headers       map[string]string
+	secretHeaders map[string]Secret
+	method        string
+	body          Secret
+	basicAuth     *BasicAuth
+}
+
+func getHTTPRequest(ctx context.Context, annotations map[string]string, resolver Resolver) (*httpRequest, error) {
+	headers, secretHeaders, err := getRequestHeaders(ctx, annotations, resolver)
+	if err != nil {
+		return nil, err
+	}
+	body, err := getRequestBody(ctx, annotations, resolver)
+	if err != nil {
+		return nil, err
+	}
+	method, err := getHTTPMethod(annotations, body != "")
+	if err != nil {
+		return nil, err
+	}
+	basicAuth, err := getBasicAuth(ctx, annotations, resolver)
+	if err != nil {
+		return nil, err
+	}
+	return &httpRequest{
+		headers:       headers,
+		secretHeaders: secretHeaders,
+		method:        method,
+		body:          body,
+		basicAuth:     basicAuth,
+	}, nil
+}
+
+// getRequestHeaders returns the plain request headers, and separately the headers which reference
+// a Secret (like "X-Api-Key: secret:name/key") with their resolved values.
+func getRequestHeaders(ctx context.Context, annotations map[string]string, resolver Resolver) (map[string]string, map[string]Secret, error) {
+	headers, err := parseRequestHeaders(annotations[AnnotationRequestHeaders])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s annotation: %w", AnnotationRequestHeaders, err)
+	}
+	var secretHeaders map[string]Secret
+	for name, value := range headers {
+		if !isSecretReference(value) {
+			continue
+		}
+		delete(headers, name)
+		resolved, ok, err := resolve(ctx, resolver, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s annotation, header %s: %w", AnnotationRequestHeaders, name, err)
+		}
+		if !ok {
+			continue
+		}
+		if secretHeaders == nil {
+			secretHeaders = make(map[string]Secret)
+		}
+		secretHeaders[name] = Secret(resolved)
+	}
+	return headers, secretHeaders, nil
+}
+
+func getHTTPMethod(annotations map[string]string, hasBody bool) (string, error) {
+	method, ok := annotations[AnnotationHTTPMethod]
+	if !ok {
+		if hasBody {
+			return http.MethodPost, nil
+		}
+		return "", nil // provider default, which is GET
+	}
+	method = strings.ToUpper(method)
+	if !slices.Contains(httpMethods, method) {
+		return "", fmt.Errorf("%s annotation should contain one of %v, got '%s'", AnnotationHTTPMethod, httpMethods, method)
+	}
+	if hasBody && (method == http.MethodGet || method == http.MethodHead) {
+		return "", fmt.Errorf("a request body can't be sent with HTTP method %s", method)
+	}
+	return method, nil
+}
+
+func getRequestBody(ctx context.Context, annotations map[string]string, resolver Resolver) (Secret, error) {
+	body, hasBody := annotations[AnnotationRequestBody]
+	bodyRef, hasBodyRef := annotations[AnnotationRequestBodyFrom]
+	if hasBody && hasBodyRef {
+		return "", fmt.Errorf("either specify %s or %s, not both", AnnotationRequestBody, AnnotationRequestBodyFrom)
+	}
+	if !hasBodyRef {
+		return Secret(body), nil
+	}
+	value, _, err := resolve(ctx, resolver, bodyRef)
+	if err != nil {
+		return "", fmt.Errorf("%s annotation: %w", AnnotationRequestBodyFrom, err)
+	}
+	return Secret(value), nil
+}
+
+// getBasicAuth reads the credentials from a Secret of type kubernetes.io/basic-auth,
+// with keys "username" and "password".
+func getBasicAuth(ctx context.Context, annotations map[string]string, resolver Resolver) (*BasicAuth, error) {
+	secretName, ok := annotations[AnnotationBasicAuthSecret]
+	if !ok {
+		return nil, nil
+	}
+	if secretName == "" || strings.Count(secretName, "/") > 1 {
+		return nil, errors.New(AnnotationBasicAuthSecret + " annotation should contain the name of a Secret, as [namespace/]name")
+	}
+	prefix := string(ReferenceSecret) + ":" + secretName + "/"
+	username, ok, err := resolve(ctx, resolver, prefix+"username")
+	if err != nil || !ok {
+		return nil, err
+	}
+	password, _, err := resolve(ctx, resolver, prefix+"password")
+	if err != nil {
+		return nil, err
+	}
+	return &BasicAuth{Username: username, Password: Secret(password)}, nil
+}
+
+// RevealRequestHeaders returns all request headers of the check, including the (revealed) secret
+// request headers. Only meant to be sent to the uptime provider, never log the result.
+func (c UptimeCheck) RevealRequestHeaders() map[string]string {
+	if len(c.SecretRequestHeaders) == 0 {
+		return c.RequestHeaders
+	}
+	result := maps.Clone(c.RequestHeaders)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	for name, value := range c.SecretRequestHeaders {
+		result[name] = value.Reveal()
+	}
+	return result
+}
+
+// Redacted returns a copy of the check with the values of all secrets replaced by a placeholder,
+// for example to show the payload sent to the uptime provider without revealing secrets.
+func (c UptimeCheck) Redacted() UptimeCheck {
+	redact := func(s Secret) Secret {
+		if s == "" {
+			return s
+		}
+		return SecretPlaceholder
+	}
+	c.RequestBody = redact(c.RequestBody)
+	if c.BasicAuth != nil {
+		c.BasicAuth = &BasicAuth{Username: c.BasicAuth.Username, Password: redact(c.BasicAuth.Password)}
+	}
+	if c.SecretRequestHeaders != nil {
+		headers := make(map[string]Secret, len(c.SecretRequestHeaders))
+		for name, value := range c.SecretRequestHeaders {
+			headers[name] = redact(value)
+		}
+		c.SecretRequestHeaders = headers
+	}
+	return c
+}
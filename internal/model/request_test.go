@@ -0,0 +1,145 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testResolver map[string]string
+
+func (t testResolver) Resolve(_ context.Context, ref Reference) (string, error) {
+	value, ok := t[ref.String()]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func TestNewUptimeCheck_HTTPRequest(t *testing.T) {
+	resolver := testResolver{
+		"configmap:wps-requests/execute": "<wps:Execute/>",
+		"secret:credentials/username":    "user",
+		"secret:credentials/password":    "pass",
+		"secret:api-keys/pdok":           "a:b,c",
+	}
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		wantMethod    string
+		wantBody      string
+		wantBasicAuth *BasicAuth
+		wantHeaders   map[string]string
+		wantErr       bool
+	}{
+		{
+			name:        "Secret request header",
+			annotations: map[string]string{"uptime.pdok.nl/request-headers": "Accept: application/json, X-Api-Key: secret:api-keys/pdok"},
+			wantHeaders: map[string]string{"Accept": "application/json", "X-Api-Key": "a:b,c"},
+		},
+		{
+			name:        "Missing secret request header",
+			annotations: map[string]string{"uptime.pdok.nl/request-headers": "X-Api-Key: secret:api-keys/other"},
+			wantErr:     true,
+		},
+		{
+			name:        "Explicit method",
+			annotations: map[string]string{"uptime.pdok.nl/http-method": "head"},
+			wantMethod:  "HEAD",
+		},
+		{
+			name:        "Body implies POST",
+			annotations: map[string]string{"uptime.pdok.nl/request-body": "service=CSW&request=GetRecords"},
+			wantMethod:  "POST",
+			wantBody:    "service=CSW&request=GetRecords",
+		},
+		{
+			name:        "Body from ConfigMap",
+			annotations: map[string]string{"uptime.pdok.nl/request-body-from": "configmap:wps-requests/execute"},
+			wantMethod:  "POST",
+			wantBody:    "<wps:Execute/>",
+		},
+		{
+			name:          "Basic auth",
+			annotations:   map[string]string{"uptime.pdok.nl/basic-auth-secret": "credentials"},
+			wantBasicAuth: &BasicAuth{Username: "user", Password: "pass"},
+		},
+		{
+			name:        "Missing secret",
+			annotations: map[string]string{"uptime.pdok.nl/basic-auth-secret": "other-credentials"},
+			wantErr:     true,
+		},
+		{
+			name:        "Invalid reference",
+			annotations: map[string]string{"uptime.pdok.nl/request-body-from": "configmap:wps-requests"},
+			wantErr:     true,
+		},
+		{
+			name:        "Both body and body reference",
+			annotations: map[string]string{"uptime.pdok.nl/request-body": "foo", "uptime.pdok.nl/request-body-from": "configmap:wps-requests/execute"},
+			wantErr:     true,
+		},
+		{
+			name:        "Body with GET",
+			annotations: map[string]string{"uptime.pdok.nl/http-method": "GET", "uptime.pdok.nl/request-body": "foo"},
+			wantErr:     true,
+		},
+		{
+			name:        "Unsupported method",
+			annotations: map[string]string{"uptime.pdok.nl/http-method": "FETCH"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.annotations["uptime.pdok.nl/id"] = "1234567890"
+			tt.annotations["uptime.pdok.nl/name"] = "Test Check"
+			tt.annotations["uptime.pdok.nl/url"] = "https://pdok.example"
+
+			check, err := NewUptimeCheck(context.TODO(), "test-ingress", tt.annotations, resolver)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMethod, check.HTTPMethod)
+			assert.Equal(t, tt.wantBody, check.RequestBody.Reveal())
+			assert.Equal(t, tt.wantBasicAuth, check.BasicAuth)
+			if tt.wantHeaders != nil {
+				assert.Equal(t, tt.wantHeaders, check.RevealRequestHeaders())
+				assert.NotContains(t, check.RequestHeaders, "X-Api-Key")
+			}
+		})
+	}
+}
+
+func TestSecret(t *testing.T) {
+	check := UptimeCheck{BasicAuth: &BasicAuth{Username: "user", Password: "s3cr3t"}}
+	result, err := json.Marshal(check)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(result), "s3cr3t")
+	assert.Equal(t, "***", fmt.Sprintf("%v", check.BasicAuth.Password))
+	assert.Equal(t, "s3cr3t", check.BasicAuth.Password.Reveal())
+}
+
+func TestUptimeCheck_Redacted(t *testing.T) {
+	check := UptimeCheck{
+		RequestHeaders:       map[string]string{"Accept": "application/json"},
+		SecretRequestHeaders: map[string]Secret{"Authorization": "Bearer s3cr3t"},
+		RequestBody:          "s3cr3t",
+		BasicAuth:            &BasicAuth{Username: "user", Password: "s3cr3t"},
+	}
+	redacted := check.Redacted()
+	assert.Equal(t, map[string]string{"Accept": "application/json", "Authorization": "***"}, redacted.RevealRequestHeaders())
+	assert.Equal(t, "***", redacted.RequestBody.Reveal())
+	assert.Equal(t, "user", redacted.BasicAuth.Username)
+	assert.Equal(t, "***", redacted.BasicAuth.Password.Reveal())
+
+	// the original check is left as-is
+	assert.Equal(t, "Bearer s3cr3t", check.SecretRequestHeaders["Authorization"].Reveal())
+	assert.Equal(t, "s3cr3t", check.BasicAuth.Password.Reveal())
+}
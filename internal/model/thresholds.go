@@ -0,0 +1,52 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// thresholds when the uptime provider should consider a check down or up again
+type thresholds struct {
+	responseTime       time.Duration
+	alertAfterFailures int
+	recoveryPeriod     time.Duration
+}
+
+func getThresholds(annotations map[string]string) (*thresholds, error) {
+	responseTime, err := getPositiveDuration(annotations, AnnotationResponseTimeThreshold)
+	if err != nil {
+		return nil, err
+	}
+	recoveryPeriod, err := getPositiveDuration(annotations, AnnotationRecoveryPeriod)
+	if err != nil {
+		return nil, err
+	}
+	result := &thresholds{
+		responseTime:   responseTime,
+		recoveryPeriod: recoveryPeriod,
+	}
+	if value, ok := annotations[AnnotationAlertAfterFailures]; ok {
+		result.alertAfterFailures, err = strconv.Atoi(value)
+		if err != nil || result.alertAfterFailures < 1 {
+			return nil, errors.New(AnnotationAlertAfterFailures + " annotation should contain a positive integer value")
+		}
+	}
+	return result, nil
+}
+
+func getPositiveDuration(annotations map[string]string, annotation string) (time.Duration, error) {
+	value, ok := annotations[annotation]
+	if !ok {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s annotation should contain a duration (e.g. 1m30s): %w", annotation, err)
+	}
+	if d <= 0 {
+		return 0, errors.New(annotation + " annotation should contain a positive duration")
+	}
+	return d, nil
+}
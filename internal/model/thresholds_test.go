@@ -0,0 +1,85 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUptimeCheck_Thresholds(t *testing.T) {
+	tests := []struct {
+		name               string
+		annotations        map[string]string
+		wantResponseTime   time.Duration
+		wantAlertAfter     int
+		wantRecoveryPeriod time.Duration
+		wantErr            bool
+	}{
+		{
+			name:        "No thresholds",
+			annotations: map[string]string{},
+		},
+		{
+			name: "All thresholds",
+			annotations: map[string]string{
+				"uptime.pdok.nl/response-time-threshold": "1500ms",
+				"uptime.pdok.nl/alert-after-failures":    "3",
+				"uptime.pdok.nl/recovery-period":         "5m",
+			},
+			wantResponseTime:   1500 * time.Millisecond,
+			wantAlertAfter:     3,
+			wantRecoveryPeriod: 5 * time.Minute,
+		},
+		{
+			name:        "Invalid response time threshold",
+			annotations: map[string]string{"uptime.pdok.nl/response-time-threshold": "1500"},
+			wantErr:     true,
+		},
+		{
+			name:        "Negative recovery period",
+			annotations: map[string]string{"uptime.pdok.nl/recovery-period": "-5m"},
+			wantErr:     true,
+		},
+		{
+			name:        "Zero failures",
+			annotations: map[string]string{"uptime.pdok.nl/alert-after-failures": "0"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.annotations["uptime.pdok.nl/id"] = "1234567890"
+			tt.annotations["uptime.pdok.nl/name"] = "Test Check"
+			tt.annotations["uptime.pdok.nl/url"] = "https://pdok.example"
+
+			check, err := NewUptimeCheck(context.TODO(), "test-ingress", tt.annotations, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantResponseTime, check.ResponseTimeThreshold)
+			assert.Equal(t, tt.wantAlertAfter, check.AlertAfterFailures)
+			assert.Equal(t, tt.wantRecoveryPeriod, check.RecoveryPeriod)
+		})
+	}
+}
+
+func TestUptimeCheck_ApplyDefaults(t *testing.T) {
+	defaults := CheckDefaults{
+		Regions:               []string{"eu"},
+		ResponseTimeThreshold: 5 * time.Second,
+		AlertAfterFailures:    2,
+		RecoveryPeriod:        time.Minute,
+	}
+	check := UptimeCheck{AlertAfterFailures: 4}
+	check.ApplyDefaults(defaults)
+	assert.Equal(t, UptimeCheck{
+		Regions:               []string{"eu"},
+		ResponseTimeThreshold: 5 * time.Second,
+		AlertAfterFailures:    4,
+		RecoveryPeriod:        time.Minute,
+	}, check)
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CheckType enum of supported types of uptime checks
+type CheckType string
+
+const (
+	CheckTypeHTTP CheckType = "http"
+	CheckTypeTCP  CheckType = "tcp"
+	CheckTypePing CheckType = "ping"
+	CheckTypeDNS  CheckType = "dns"
+)
+
+var checkTypes = []CheckType{CheckTypeHTTP, CheckTypeTCP, CheckTypePing, CheckTypeDNS}
+
+// getCheckType returns the type of check as specified by the type annotation, or otherwise derived
+// from the scheme of the URL. The URL scheme should match the type of check, e.g. "tcp://host:5432".
+func getCheckType(checkURL string, annotations map[string]string) (CheckType, error) {
+	parsedURL, err := url.ParseRequestURI(checkURL)
+	if err != nil {
+		return "", fmt.Errorf("%s annotation should contain a valid URL: %w", AnnotationURL, err)
+	}
+	scheme := strings.ToLower(parsedURL.Scheme)
+	urlType := CheckType(scheme)
+	if scheme == "https" {
+		urlType = CheckTypeHTTP
+	}
+
+	checkType := urlType
+	if value, ok := annotations[AnnotationType]; ok {
+		checkType = CheckType(strings.ToLower(value))
+	}
+	if !slices.Contains(checkTypes, checkType) {
+		return "", fmt.Errorf("unsupported check type '%s', should be one of %v", checkType, checkTypes)
+	}
+	if checkType != urlType {
+		return "", fmt.Errorf("URL %s doesn't match check type '%s', expected a URL like %s",
+			checkURL, checkType, exampleURL(checkType))
+	}
+	if checkType == CheckTypeTCP && parsedURL.Port() == "" {
+		return "", fmt.Errorf("URL %s of TCP check should contain a port, e.g. %s", checkURL, exampleURL(checkType))
+	}
+	return checkType, nil
+}
+
+// validateCheckType rejects annotations that don't apply to the type of check, instead
+// of silently ignoring them.
+func validateCheckType(check *UptimeCheck) error {
+	var errs []error
+	reject := func(annotation string, isSet bool, types ...CheckType) {
+		if isSet && !slices.Contains(types, check.Type) {
+			errs = append(errs, fmt.Errorf("%s annotation isn't supported for check type '%s', only for %v",
+				annotation, check.Type, types))
+		}
+	}
+	reject(AnnotationRequestHeaders, len(check.RequestHeaders) > 0 || len(check.SecretRequestHeaders) > 0, CheckTypeHTTP)
+	reject(AnnotationStringContains, check.StringContains != "", CheckTypeHTTP)
+	reject(AnnotationStringNotContains, check.StringNotContains != "", CheckTypeHTTP)
+	reject(AnnotationHTTPMethod, check.HTTPMethod != "", CheckTypeHTTP)
+	reject(AnnotationRequestBody, check.RequestBody != "", CheckTypeHTTP)
+	reject(AnnotationBasicAuthSecret, check.BasicAuth != nil, CheckTypeHTTP)
+	reject(AnnotationExpectedStatusCodes, len(check.ExpectedStatusCodes) > 0, CheckTypeHTTP)
+	reject(AnnotationStringMatchesRegex, check.StringMatchesRegex != "", CheckTypeHTTP)
+	reject(AnnotationJSONPath, check.JSONPath != nil, CheckTypeHTTP)
+	reject(AnnotationTLSExpiry, check.TLSExpiryAlertDays > 0, CheckTypeHTTP)
+	reject(AnnotationTCPStringToSend, check.TCPStringToSend != "", CheckTypeTCP)
+	reject(AnnotationTCPStringToExpect, check.TCPStringToExpect != "", CheckTypeTCP)
+	reject(AnnotationDNSExpectedIP, check.DNSExpectedIP != "", CheckTypeDNS)
+	reject(AnnotationDNSNameserver, check.DNSNameserver != "", CheckTypeDNS)
+
+	if check.Type == CheckTypeDNS && (check.DNSExpectedIP == "" || check.DNSNameserver == "") {
+		errs = append(errs, fmt.Errorf("both %s and %s annotations are required for check type '%s'",
+			AnnotationDNSExpectedIP, AnnotationDNSNameserver, check.Type))
+	}
+	return errors.Join(errs...)
+}
+
+func exampleURL(checkType CheckType) string {
+	switch checkType {
+	case CheckTypeHTTP:
+		return "https://site.example/path"
+	case CheckTypeTCP:
+		return "tcp://host.example:5432"
+	case CheckTypePing, CheckTypeDNS:
+		return string(checkType) + "://host.example"
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUptimeCheck_Types(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantType    CheckType
+		wantErr     bool
+	}{
+		{
+			name:        "HTTPS URL implies HTTP check",
+			annotations: map[string]string{"uptime.pdok.nl/url": "https://pdok.example"},
+			wantType:    CheckTypeHTTP,
+		},
+		{
+			name:        "TCP URL implies TCP check",
+			annotations: map[string]string{"uptime.pdok.nl/url": "tcp://pdok.example:5432"},
+			wantType:    CheckTypeTCP,
+		},
+		{
+			name: "Explicit TCP check with string to send and expect",
+			annotations: map[string]string{
+				"uptime.pdok.nl/type":                 "TCP",
+				"uptime.pdok.nl/url":                  "tcp://pdok.example:6379",
+				"uptime.pdok.nl/tcp-string-to-send":   "PING",
+				"uptime.pdok.nl/tcp-string-to-expect": "PONG",
+			},
+			wantType: CheckTypeTCP,
+		},
+		{
+			name:        "TCP check without port",
+			annotations: map[string]string{"uptime.pdok.nl/url": "tcp://pdok.example"},
+			wantErr:     true,
+		},
+		{
+			name:        "Ping check",
+			annotations: map[string]string{"uptime.pdok.nl/url": "ping://pdok.example"},
+			wantType:    CheckTypePing,
+		},
+		{
+			name: "DNS check",
+			annotations: map[string]string{
+				"uptime.pdok.nl/url":             "dns://pdok.example",
+				"uptime.pdok.nl/dns-expected-ip": "192.0.2.1",
+				"uptime.pdok.nl/dns-nameserver":  "ns1.example",
+			},
+			wantType: CheckTypeDNS,
+		},
+		{
+			name:        "DNS check without nameserver",
+			annotations: map[string]string{"uptime.pdok.nl/url": "dns://pdok.example", "uptime.pdok.nl/dns-expected-ip": "192.0.2.1"},
+			wantErr:     true,
+		},
+		{
+			name:        "Type doesn't match URL",
+			annotations: map[string]string{"uptime.pdok.nl/type": "ping", "uptime.pdok.nl/url": "https://pdok.example"},
+			wantErr:     true,
+		},
+		{
+			name:        "Unsupported type",
+			annotations: map[string]string{"uptime.pdok.nl/url": "udp://pdok.example:53"},
+			wantErr:     true,
+		},
+		{
+			name: "HTTP annotation on ping check",
+			annotations: map[string]string{
+				"uptime.pdok.nl/url": "ping://pdok.example",
+				"uptime.pdok.nl/response-check-for-string-contains": "foo",
+			},
+			wantErr: true,
+		},
+		{
+			name: "TCP annotation on HTTP check",
+			annotations: map[string]string{
+				"uptime.pdok.nl/url":                "https://pdok.example",
+				"uptime.pdok.nl/tcp-string-to-send": "PING",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.annotations["uptime.pdok.nl/id"] = "1234567890"
+			tt.annotations["uptime.pdok.nl/name"] = "Test Check"
+
+			check, err := NewUptimeCheck(context.TODO(), "test-ingress", tt.annotations, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantType, check.Type)
+		})
+	}
+}
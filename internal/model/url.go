@@ -0,0 +1,25 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var defaultPorts = map[string]int{
+	"http":  80,
+	"https": 443,
+}
+
+// GetPort returns the port of the given URL, or the default port of the
+// URL scheme when the port isn't explicitly specified.
+func GetPort(checkURL *url.URL) (int, error) {
+	if port := checkURL.Port(); port != "" {
+		return strconv.Atoi(port)
+	}
+	if port, ok := defaultPorts[strings.ToLower(checkURL.Scheme)]; ok {
+		return port, nil
+	}
+	return -1, fmt.Errorf("no port specified in URL %s and no default port known for scheme '%s'", checkURL, checkURL.Scheme)
+}
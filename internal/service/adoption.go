@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// adoptUnmanagedCheck adopts an existing unmanaged check matching the given (new) check according to the
+// adoption policy, so the subsequent create-or-update updates that check instead of creating a duplicate.
+// Failures are logged, in which case a new check is created as usual.
+func (r *UptimeCheckService) adoptUnmanagedCheck(ctx context.Context, check *m.UptimeCheck) {
+	adopter, ok := r.provider.(Adopter)
+	if !ok || r.adoptionPolicy == "" || r.adoptionPolicy == m.AdoptionPolicyNone {
+		return
+	}
+	var providerID string
+	err := r.withProvider(ctx, func() (err error) {
+		providerID, err = adopter.FindUnmanagedCheck(ctx, *check, r.adoptionPolicy)
+		return err
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to find unmanaged check to adopt", "check", check.ID)
+		return
+	}
+	if providerID == "" {
+		return
+	}
+	prefix := ""
+	if r.dryRun {
+		prefix = "[dry-run] would have "
+	} else {
+		err = r.withProvider(ctx, func() error { return adopter.AdoptCheck(ctx, providerID, *check) })
+	}
+	if err != nil {
+		msg := fmt.Sprintf("adoption of existing check %s for uptime check '%s' (id: %s) failed.", providerID, check.Name, check.ID)
+		log.FromContext(ctx).Error(err, msg, "check", check)
+		if r.slack != nil {
+			r.slack.Send(ctx, ":large_red_square: "+msg)
+		}
+		return
+	}
+	msg := fmt.Sprintf("%sadopted existing check %s for uptime check '%s' (id: %s), instead of creating a new check.",
+		prefix, providerID, check.Name, check.ID)
+	log.FromContext(ctx).Info(msg, "policy", r.adoptionPolicy)
+	if r.slack != nil {
+		r.slack.Send(ctx, ":handshake: "+msg)
+	}
+}
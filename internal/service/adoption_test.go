@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type adopterTestProvider struct {
+	*importTestProvider
+	lookups int
+}
+
+func (t *adopterTestProvider) FindUnmanagedCheck(_ context.Context, check m.UptimeCheck, policy m.AdoptionPolicy) (string, error) {
+	t.lookups++
+	if _, exists := t.checks[check.ID]; exists {
+		return "", nil
+	}
+	for _, unmanaged := range t.unmanaged {
+		if _, adopted := t.adopted[unmanaged.ProviderID]; !adopted && policy.Matches(check, unmanaged.UptimeCheck) {
+			return unmanaged.ProviderID, nil
+		}
+	}
+	return "", nil
+}
+
+func TestUptimeCheckService_Mutate_AdoptsUnmanagedChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      m.AdoptionPolicy
+		dryRun      bool
+		wantAdopted map[string]string
+		wantLookups int
+	}{
+		{
+			name:        "No policy",
+			wantAdopted: map[string]string{},
+		},
+		{
+			name:        "None policy",
+			policy:      m.AdoptionPolicyNone,
+			wantAdopted: map[string]string{},
+		},
+		{
+			name:        "URL policy",
+			policy:      m.AdoptionPolicyURL,
+			wantAdopted: map[string]string{"1": "id"},
+			wantLookups: 1,
+		},
+		{
+			name:        "Name and URL policy",
+			policy:      m.AdoptionPolicyNameAndURL,
+			wantAdopted: map[string]string{},
+			wantLookups: 1,
+		},
+		{
+			name:        "Dry-run",
+			policy:      m.AdoptionPolicyURL,
+			dryRun:      true,
+			wantAdopted: map[string]string{},
+			wantLookups: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &adopterTestProvider{importTestProvider: &importTestProvider{
+				testProvider: newTestProvider(),
+				unmanaged:    []m.UnmanagedCheck{unmanagedCheck("1", "Manually created", "https://pdok.example/")},
+				adopted:      make(map[string]string),
+			}}
+			service := New(WithProvider(provider), WithAdoptionPolicy(tt.policy), WithDryRun(tt.dryRun))
+			annotations := map[string]string{
+				m.AnnotationID:   "id",
+				m.AnnotationName: "Test Check",
+				m.AnnotationURL:  "https://pdok.example",
+			}
+			result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+			assert.Equal(t, tt.wantAdopted, provider.adopted)
+			assert.Equal(t, tt.wantLookups, provider.lookups)
+
+			if tt.dryRun {
+				return
+			}
+			// once applied, there's no need to look for unmanaged checks anymore
+			annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+			annotations[m.AnnotationTags] = "changed"
+			service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+			assert.Equal(t, tt.wantLookups, provider.lookups)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+)
+
+// splitStringAssertions splits checks with both a string-contains and a string-not-contains assertion into two
+// checks, when the provider can't combine these assertions in one check. The additional check is part of the same
+// route, so it's created, updated and deleted along with the original check. Returns a warning when the ID of the
+// additional check exceeds the max ID length of the provider, in which case the string-not-contains is ignored.
+func (r *UptimeCheckService) splitStringAssertions(checks []m.UptimeCheck) (result []m.UptimeCheck, warnings []string) {
+	splitter, ok := r.provider.(StringAssertionSplitter)
+	if !ok {
+		return checks, nil
+	}
+	for _, check := range checks {
+		notContainsID, assertsBoth := check.NotContainsCheckID()
+		if !assertsBoth {
+			result = append(result, check)
+			continue
+		}
+		if maxLength := splitter.MaxCheckIDLength(); maxLength > 0 && len(notContainsID) > maxLength {
+			check.StringNotContains = ""
+			result = append(result, check)
+			warnings = append(warnings, fmt.Sprintf("uptime check '%s' (id: %s) asserts both %s and %s, which the "+
+				"uptime provider can't combine in one check. The ID of the additional check (%s) would exceed %d "+
+				"characters, so %s is ignored. Use a shorter ID.", check.Name, check.ID, m.AnnotationStringContains,
+				m.AnnotationStringNotContains, notContainsID, maxLength, m.AnnotationStringNotContains))
+			continue
+		}
+		notContains := check
+		notContains.ID = notContainsID
+		notContains.Name = check.Name + " (not contains)"
+		notContains.Tags = slices.Clone(check.Tags)
+		notContains.RequestHeaders = maps.Clone(check.RequestHeaders)
+		notContains.StringContains = ""
+		check.StringNotContains = ""
+		result = append(result, check, notContains)
+	}
+	return result, warnings
+}
+
+// checkResponseAssertions returns an error when the provider can't apply all response assertions of the given
+// check. Such a check is rejected as invalid, rather than applied without (some of) its assertions.
+func (r *UptimeCheckService) checkResponseAssertions(check m.UptimeCheck) error {
+	unsupported := check.ResponseAssertionAnnotations()
+	if supporter, ok := r.provider.(ResponseAssertionSupporter); ok {
+		unsupported = supporter.UnsupportedResponseAssertions(check)
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+	return fmt.Errorf("uptime check '%s' (id: %s) uses %s, which the uptime provider doesn't support (in this combination)",
+		check.Name, check.ID, strings.Join(unsupported, ", "))
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// dryRunMutation logs (and reports on Slack) the given mutation of the check instead of executing it. When
+// supported by the provider this includes whether the check would be created or updated and the payload.
+func (r *UptimeCheckService) dryRunMutation(ctx context.Context, mutation m.Mutation, check *m.UptimeCheck) {
+	operation := "delete"
+	var payload []byte
+	if mutation == m.CreateOrUpdate {
+		var err error
+		if operation, payload, err = r.describeCreateOrUpdate(ctx, *check); err != nil {
+			msg := fmt.Sprintf("[dry-run] %s of uptime check '%s' (id: %s) would fail.", string(mutation), check.Name, check.ID)
+			log.FromContext(ctx).Error(err, msg, "check", check)
+			if r.slack != nil {
+				r.slack.Send(ctx, ":large_red_square: "+msg)
+			}
+			return
+		}
+	}
+	msg := fmt.Sprintf("[dry-run] would %s uptime check '%s' (id: %s).", operation, check.Name, check.ID)
+	log.FromContext(ctx).Info(msg, "check", check, "payload", string(payload))
+	if r.slack == nil {
+		return
+	}
+	if payload != nil {
+		msg += fmt.Sprintf("\n```%s```", payload)
+	}
+	r.slack.Send(ctx, ":test_tube: "+msg)
+}
+
+// describeCreateOrUpdate returns whether the given check would be created or updated, and the
+// provider-specific payload (with secrets redacted). Only when supported by the provider.
+func (r *UptimeCheckService) describeCreateOrUpdate(ctx context.Context, check m.UptimeCheck) (string, []byte, error) {
+	dryRunner, ok := r.provider.(DryRunner)
+	if !ok {
+		return "create or update", nil, nil
+	}
+	var exists bool
+	err := r.withProvider(ctx, func() (err error) {
+		exists, err = dryRunner.CheckExists(ctx, check)
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	payload, err := dryRunner.Payload(check.Redacted(), !exists)
+	if err != nil {
+		return "", nil, err
+	}
+	operation := "update"
+	if !exists {
+		operation = "create"
+	}
+	return operation, payload, nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	hostRule       = regexp.MustCompile("Host\\(([^)]*)\\)")
+	pathPrefixRule = regexp.MustCompile("Path(?:Prefix)?\\(([^)]*)\\)")
+	ruleArgument   = regexp.MustCompile("`([^`]*)`")
+)
+
+// Import unmanaged checks with the uptime provider, matched to ingress routes
+type Import struct {
+	Matched   []ImportedCheck  `json:"matched"`
+	Unmatched []UnmatchedCheck `json:"unmatched"`
+}
+
+// ImportedCheck unmanaged check matched to an ingress route, with the annotations to add to the route
+type ImportedCheck struct {
+	Route       string            `json:"route"`
+	ProviderID  string            `json:"provider_id"`
+	Name        string            `json:"name"`
+	URL         string            `json:"url"`
+	Annotations map[string]string `json:"annotations"`
+
+	// Omitted annotations which aren't imported since these may contain secrets, to be added manually
+	Omitted []string `json:"omitted,omitempty"`
+
+	check m.UptimeCheck // with the newly assigned ID
+}
+
+// UnmatchedCheck unmanaged check which couldn't be matched to an ingress route, with the reason
+type UnmatchedCheck struct {
+	ProviderID string `json:"provider_id"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Reason     string `json:"reason"`
+}
+
+// ImportRoute ingress route to import unmanaged checks into
+type ImportRoute struct {
+	Name        string // namespace/name
+	Endpoints   []RouteEndpoint
+	Annotations map[string]string
+}
+
+// RouteEndpoint host and path prefix served by an ingress route
+type RouteEndpoint struct {
+	Host       string
+	PathPrefix string // empty for all paths
+}
+
+// EndpointsOfRule returns the endpoints served by the given Traefik match rule, for example
+// "Host(`site.example`) && PathPrefix(`/path`)". Rules with multiple hosts and/or paths
+// result in all combinations. Rules without a host don't result in any endpoints.
+func EndpointsOfRule(rule string) []RouteEndpoint {
+	var hosts, pathPrefixes []string
+	for _, match := range hostRule.FindAllStringSubmatch(rule, -1) {
+		hosts = append(hosts, ruleArguments(match[1])...)
+	}
+	for _, match := range pathPrefixRule.FindAllStringSubmatch(rule, -1) {
+		pathPrefixes = append(pathPrefixes, ruleArguments(match[1])...)
+	}
+	if len(pathPrefixes) == 0 {
+		pathPrefixes = []string{""}
+	}
+	var result []RouteEndpoint
+	for _, host := range hosts {
+		for _, pathPrefix := range pathPrefixes {
+			result = append(result, RouteEndpoint{Host: strings.ToLower(host), PathPrefix: pathPrefix})
+		}
+	}
+	return result
+}
+
+func ruleArguments(arguments string) []string {
+	var result []string
+	for _, match := range ruleArgument.FindAllStringSubmatch(arguments, -1) {
+		result = append(result, match[1])
+	}
+	return result
+}
+
+// Import matches the checks with the uptime provider which aren't managed by the operator to the given
+// ingress routes, by host and (the longest) path prefix of the URL of the check. Returns the annotations
+// to add to the routes, with a newly assigned ID per check. Nothing is changed, see Adopt. Routes which
+// already contain checks get the imported checks as named groups of annotations, as do routes to which
+// multiple checks are matched. Requires a provider which supports importing.
+func (r *UptimeCheckService) Import(ctx context.Context, routes []ImportRoute) (*Import, error) {
+	importer, ok := r.provider.(Importer)
+	if !ok {
+		return nil, errors.New("uptime provider doesn't support importing checks")
+	}
+	var unmanaged []m.UnmanagedCheck
+	err := r.withProvider(ctx, func() (err error) {
+		unmanaged, err = importer.ListUnmanagedChecks(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unmanaged checks at the uptime provider: %w", err)
+	}
+	slices.SortFunc(unmanaged, func(a, b m.UnmanagedCheck) int {
+		return cmp.Or(strings.Compare(a.URL, b.URL), strings.Compare(a.ProviderID, b.ProviderID))
+	})
+
+	result := &Import{}
+	matched := make(map[string][]m.UnmanagedCheck)
+	for _, check := range unmanaged {
+		route, reason := matchRoute(check.UptimeCheck, routes)
+		if route == nil {
+			result.Unmatched = append(result.Unmatched, UnmatchedCheck{
+				ProviderID: check.ProviderID, Name: check.Name, URL: check.URL, Reason: reason,
+			})
+			continue
+		}
+		matched[route.Name] = append(matched[route.Name], check)
+	}
+	for _, route := range routes {
+		asGroups := len(matched[route.Name]) > 1 || m.HasUptimeChecks(route.Annotations)
+		for _, check := range matched[route.Name] {
+			check.ID = newCheckID()
+			annotations := check.Annotations()
+			if asGroups {
+				annotations = toGroup("import-"+check.ProviderID, annotations)
+			}
+			result.Matched = append(result.Matched, ImportedCheck{
+				Route:       route.Name,
+				ProviderID:  check.ProviderID,
+				Name:        check.Name,
+				URL:         check.URL,
+				Annotations: annotations,
+				Omitted:     check.OmittedAnnotations(),
+				check:       check.UptimeCheck,
+			})
+		}
+	}
+	return result, nil
+}
+
+// Adopt takes ownership of the given imported check at the uptime provider, so the operator manages the
+// check from now on instead of creating a duplicate. Should be done before adding the annotations to the route.
+func (r *UptimeCheckService) Adopt(ctx context.Context, imported ImportedCheck) error {
+	importer, ok := r.provider.(Importer)
+	if !ok {
+		return errors.New("uptime provider doesn't support importing checks")
+	}
+	log.FromContext(ctx).Info("adopting check", "route", imported.Route, "provider ID", imported.ProviderID,
+		"check", imported.check.ID)
+	return r.withProvider(ctx, func() error {
+		return importer.AdoptCheck(ctx, imported.ProviderID, imported.check)
+	})
+}
+
+// matchRoute returns the route with the endpoint best matching the URL of the given check,
+// or otherwise the reason why there's no match
+func matchRoute(check m.UptimeCheck, routes []ImportRoute) (*ImportRoute, string) {
+	checkURL, err := url.ParseRequestURI(check.URL)
+	if check.URL == "" || err != nil {
+		return nil, "check has no (valid) URL"
+	}
+	host := strings.ToLower(checkURL.Hostname())
+	var best []*ImportRoute
+	bestLength := -1
+	for i := range routes {
+		if _, ignore := routes[i].Annotations[m.AnnotationIgnore]; ignore {
+			continue
+		}
+		for _, endpoint := range routes[i].Endpoints {
+			// only HTTP checks have a path, other types match on host only
+			if endpoint.Host != host || (check.Type == m.CheckTypeHTTP && !strings.HasPrefix(checkURL.Path, endpoint.PathPrefix)) {
+				continue
+			}
+			length := len(endpoint.PathPrefix)
+			if check.Type != m.CheckTypeHTTP {
+				length = 0
+			}
+			switch {
+			case length > bestLength:
+				best, bestLength = []*ImportRoute{&routes[i]}, length
+			case length == bestLength && !slices.Contains(best, &routes[i]):
+				best = append(best, &routes[i])
+			}
+		}
+	}
+	switch len(best) {
+	case 0:
+		return nil, "no ingress route with host " + host
+	case 1:
+		return best[0], ""
+	default:
+		names := make([]string, 0, len(best))
+		for _, route := range best {
+			names = append(names, route.Name)
+		}
+		return nil, "matches multiple ingress routes: " + strings.Join(names, ", ")
+	}
+}
+
+// toGroup converts regular uptime annotations to a named group, e.g. "uptime.pdok.nl/url"
+// to "uptime.pdok.nl/<name>.url"
+func toGroup(name string, annotations map[string]string) map[string]string {
+	result := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		result[m.AnnotationBase+"/"+name+"."+strings.TrimPrefix(key, m.AnnotationBase+"/")] = value
+	}
+	return result
+}
+
+// newCheckID returns a random ID for an imported check
+func newCheckID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
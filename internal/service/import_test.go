@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type importTestProvider struct {
+	*testProvider
+	unmanaged []m.UnmanagedCheck
+	adopted   map[string]string // check ID by provider ID
+}
+
+func (t *importTestProvider) ListUnmanagedChecks(_ context.Context) ([]m.UnmanagedCheck, error) {
+	return t.unmanaged, nil
+}
+
+func (t *importTestProvider) AdoptCheck(_ context.Context, providerID string, check m.UptimeCheck) error {
+	t.adopted[providerID] = check.ID
+	return nil
+}
+
+func unmanagedCheck(providerID string, name string, url string) m.UnmanagedCheck {
+	return m.UnmanagedCheck{ProviderID: providerID, UptimeCheck: m.UptimeCheck{Name: name, Type: m.CheckTypeHTTP, URL: url}}
+}
+
+func TestEndpointsOfRule(t *testing.T) {
+	tests := []struct {
+		rule string
+		want []RouteEndpoint
+	}{
+		{
+			rule: "Host(`site.example`)",
+			want: []RouteEndpoint{{Host: "site.example"}},
+		},
+		{
+			rule: "Host(`Site.example`) && PathPrefix(`/wms`)",
+			want: []RouteEndpoint{{Host: "site.example", PathPrefix: "/wms"}},
+		},
+		{
+			rule: "(Host(`a.example`) || Host(`b.example`)) && (Path(`/wms`) || PathPrefix(`/wfs`))",
+			want: []RouteEndpoint{
+				{Host: "a.example", PathPrefix: "/wms"},
+				{Host: "a.example", PathPrefix: "/wfs"},
+				{Host: "b.example", PathPrefix: "/wms"},
+				{Host: "b.example", PathPrefix: "/wfs"},
+			},
+		},
+		{
+			rule: "PathPrefix(`/wms`)",
+			want: nil,
+		},
+		{
+			rule: "HostRegexp(`.+\\.example`)",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			assert.Equal(t, tt.want, EndpointsOfRule(tt.rule))
+		})
+	}
+}
+
+func TestUptimeCheckService_Import(t *testing.T) {
+	provider := &importTestProvider{
+		testProvider: newTestProvider(),
+		unmanaged: []m.UnmanagedCheck{
+			unmanagedCheck("1", "WMS", "https://site.example/wms?request=GetCapabilities"),
+			unmanagedCheck("2", "WFS", "https://site.example/wfs"),
+			unmanagedCheck("3", "Other WFS", "https://site.example/wfs/other"),
+			unmanagedCheck("4", "Homepage", "https://site.example/"),
+			unmanagedCheck("5", "Elsewhere", "https://elsewhere.example/"),
+			unmanagedCheck("6", "Shared", "https://shared.example/"),
+			unmanagedCheck("7", "Ignored", "https://ignored.example/"),
+		},
+		adopted: make(map[string]string),
+	}
+	service := New(WithProvider(provider))
+	routes := []ImportRoute{
+		{Name: "ns/wms", Endpoints: []RouteEndpoint{{Host: "site.example", PathPrefix: "/wms"}}},
+		{Name: "ns/wfs", Endpoints: []RouteEndpoint{{Host: "site.example", PathPrefix: "/wfs"}}},
+		{Name: "ns/site", Endpoints: []RouteEndpoint{{Host: "site.example"}}, Annotations: map[string]string{
+			m.AnnotationID:   "existing",
+			m.AnnotationName: "Existing",
+			m.AnnotationURL:  "https://site.example/about",
+		}},
+		{Name: "ns/shared-1", Endpoints: []RouteEndpoint{{Host: "shared.example"}}},
+		{Name: "ns/shared-2", Endpoints: []RouteEndpoint{{Host: "shared.example"}}},
+		{Name: "ns/ignored", Endpoints: []RouteEndpoint{{Host: "ignored.example"}}, Annotations: map[string]string{
+			m.AnnotationIgnore: "true",
+		}},
+	}
+
+	result, err := service.Import(context.TODO(), routes)
+	require.NoError(t, err)
+
+	matched := make(map[string]ImportedCheck)
+	for _, imported := range result.Matched {
+		matched[imported.ProviderID] = imported
+	}
+	require.Len(t, matched, 4)
+
+	// single check on a route without checks results in regular annotations
+	assert.Equal(t, "ns/wms", matched["1"].Route)
+	assert.Equal(t, "WMS", matched["1"].Annotations[m.AnnotationName])
+	assert.Len(t, matched["1"].Annotations[m.AnnotationID], 32)
+
+	// multiple checks on a route results in groups
+	assert.Equal(t, "ns/wfs", matched["2"].Route)
+	assert.Equal(t, "https://site.example/wfs", matched["2"].Annotations["uptime.pdok.nl/import-2.url"])
+	assert.Equal(t, "ns/wfs", matched["3"].Route)
+	assert.Equal(t, "Other WFS", matched["3"].Annotations["uptime.pdok.nl/import-3.name"])
+
+	// route with existing check results in a group, which is valid next to the existing check
+	assert.Equal(t, "ns/site", matched["4"].Route)
+	annotations := routes[2].Annotations
+	for key, value := range matched["4"].Annotations {
+		annotations[key] = value
+	}
+	checks, err := m.NewUptimeChecks(context.TODO(), "ns/site", annotations, nil)
+	require.NoError(t, err)
+	assert.Len(t, checks, 2)
+
+	assert.Equal(t, []UnmatchedCheck{
+		{ProviderID: "5", Name: "Elsewhere", URL: "https://elsewhere.example/", Reason: "no ingress route with host elsewhere.example"},
+		{ProviderID: "7", Name: "Ignored", URL: "https://ignored.example/", Reason: "no ingress route with host ignored.example"},
+		{ProviderID: "6", Name: "Shared", URL: "https://shared.example/", Reason: "matches multiple ingress routes: ns/shared-1, ns/shared-2"},
+	}, result.Unmatched)
+
+	require.NoError(t, service.Adopt(context.TODO(), matched["1"]))
+	assert.Equal(t, map[string]string{"1": matched["1"].Annotations[m.AnnotationID]}, provider.adopted)
+}
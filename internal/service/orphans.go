@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DeleteOrphanedChecks deletes the checks at the uptime provider with the owner tag of this operator instance,
+// which don't belong to any of the given ingress routes (annotations by route name) anymore. For example because
+// a route was removed while the operator wasn't running. Only applies when both deletes and orphan deletes are
+// enabled, an owner tag is configured and the provider is able to list checks. Nothing is deleted when one of the
+// routes has invalid annotations, to avoid removing checks because of a typo.
+func (r *UptimeCheckService) DeleteOrphanedChecks(ctx context.Context, routeAnnotations map[string]map[string]string) error {
+	lister, ok := r.provider.(CheckLister)
+	if !ok || !r.enableDeletes || !r.deleteOrphans {
+		return nil
+	}
+	if r.ownerTag == "" {
+		log.FromContext(ctx).Info("not removing orphaned checks, since no owner tag is configured")
+		return nil
+	}
+	inUse := make(map[string]bool)
+	for ingressName, annotations := range routeAnnotations {
+		// to be safe also keep checks which were applied before, stale checks
+		// are removed when the route itself is mutated.
+		lastApplied, _ := m.GetLastApplied(annotations)
+		for _, id := range lastApplied.IDs {
+			inUse[id] = true
+		}
+		if !m.HasUptimeChecks(annotations) {
+			continue
+		}
+		checks, err := m.NewUptimeChecks(ctx, ingressName, annotations, nil)
+		if err != nil {
+			log.FromContext(ctx).Info("not removing orphaned checks, since ingress route has invalid annotations",
+				"route", ingressName, "error", err.Error())
+			return nil
+		}
+		checks, _, _ = r.prepareChecks(m.Delete, checks)
+		for _, check := range checks {
+			inUse[check.ID] = true
+		}
+	}
+	var ids []string
+	err := r.withProvider(ctx, func() (err error) {
+		ids, err = lister.ListCheckIDs(ctx, r.ownerTag)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list checks at the uptime provider: %w", err)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		if inUse[id] {
+			continue
+		}
+		log.FromContext(ctx).Info("check doesn't belong to any ingress route", "check", id)
+		orphan := m.UptimeCheck{ID: id, Name: "orphaned check"}
+		r.deleteCheck(ctx, &orphan)
+	}
+	return nil
+}
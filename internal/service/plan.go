@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+)
+
+// Plan the mutations of uptime checks needed to bring the uptime provider in line with the ingress routes
+type Plan struct {
+	Create    []PlannedCheck `json:"create"`
+	Update    []PlannedCheck `json:"update"`
+	Unchanged []PlannedCheck `json:"unchanged"`
+
+	// Orphaned checks managed by the operator which don't belong to any ingress route (anymore)
+	Orphaned []string `json:"orphaned"`
+
+	// Invalid ingress routes which can't be planned because of invalid annotations
+	Invalid []InvalidRoute `json:"invalid"`
+}
+
+// PlannedCheck uptime check of an ingress route, with the changed fields in case of an update
+type PlannedCheck struct {
+	Route    string        `json:"route"`
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Changes  []FieldChange `json:"changes,omitempty"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+// FieldChange field of the provider-specific payload of an uptime check which differs from the current value
+type FieldChange struct {
+	Field   string `json:"field"`
+	Current any    `json:"current"`
+	Desired any    `json:"desired"`
+}
+
+// InvalidRoute ingress route with invalid annotations, with the reason
+type InvalidRoute struct {
+	Route string `json:"route"`
+	Error string `json:"error"`
+}
+
+// Plan computes the mutations of uptime checks needed to bring the uptime provider in line with the given ingress
+// routes (annotations by route name), without changing anything. References to Secrets and ConfigMaps aren't
+// resolved, so fields containing secrets aren't compared. Requires a provider which supports planning.
+func (r *UptimeCheckService) Plan(ctx context.Context, routeAnnotations map[string]map[string]string) (*Plan, error) {
+	planner, ok := r.provider.(Planner)
+	if !ok {
+		return nil, errors.New("uptime provider doesn't support planning")
+	}
+	var listedIDs []string
+	err := r.withProvider(ctx, func() (err error) {
+		listedIDs, err = planner.ListCheckIDs(ctx, r.ownerTag)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checks at the uptime provider: %w", err)
+	}
+
+	plan := &Plan{}
+	inUse := make(map[string]bool)
+	now := time.Now()
+	for _, route := range slices.Sorted(maps.Keys(routeAnnotations)) {
+		annotations := routeAnnotations[route]
+		// like DeleteOrphanedChecks, checks which were applied before aren't orphaned
+		lastApplied, _ := m.GetLastApplied(annotations)
+		for _, id := range lastApplied.IDs {
+			inUse[id] = true
+		}
+		if !m.HasUptimeChecks(annotations) {
+			continue
+		}
+		if _, ignore := annotations[m.AnnotationIgnore]; ignore {
+			// the checks of an ignored route are left as is
+			if checks, err := m.NewUptimeChecks(ctx, route, annotations, nil); err == nil {
+				for _, check := range checks {
+					inUse[check.ID] = true
+				}
+			}
+			continue
+		}
+		var warnings []string
+		checks, err := m.NewUptimeChecks(ctx, route, annotations, nil)
+		if err == nil {
+			checks, warnings, err = r.prepareChecks(m.CreateOrUpdate, checks)
+		}
+		if err != nil {
+			// the checks of an invalid route are left as is
+			plan.Invalid = append(plan.Invalid, InvalidRoute{Route: route, Error: err.Error()})
+			continue
+		}
+		for i := range checks {
+			inUse[checks[i].ID] = true
+			r.handleMaintenance(&checks[i], now)
+			planned, exists, err := r.planCheck(ctx, planner, route, checks[i])
+			if err != nil {
+				return nil, err
+			}
+			planned.Warnings = warningsOf(checks[i], warnings)
+			switch {
+			case !exists:
+				plan.Create = append(plan.Create, planned)
+			case len(planned.Changes) > 0:
+				plan.Update = append(plan.Update, planned)
+			default:
+				plan.Unchanged = append(plan.Unchanged, planned)
+			}
+		}
+	}
+	for _, id := range slices.Sorted(slices.Values(listedIDs)) {
+		if !inUse[id] {
+			plan.Orphaned = append(plan.Orphaned, id)
+		}
+	}
+	return plan, nil
+}
+
+func (r *UptimeCheckService) planCheck(ctx context.Context, planner Planner, route string,
+	check m.UptimeCheck) (planned PlannedCheck, exists bool, err error) {
+	planned = PlannedCheck{Route: route, ID: check.ID, Name: check.Name}
+	var current []byte
+	err = r.withProvider(ctx, func() (err error) {
+		if exists, err = planner.CheckExists(ctx, check); err != nil || !exists {
+			return err
+		}
+		current, err = planner.CurrentPayload(ctx, check)
+		return err
+	})
+	if err != nil || !exists {
+		return planned, exists, err
+	}
+	desired, err := planner.Payload(check.Redacted(), false)
+	if err != nil {
+		return planned, exists, fmt.Errorf("failed to plan uptime check %s of ingress route %s: %w", check.ID, route, err)
+	}
+	planned.Changes, err = diffPayloads(current, desired)
+	return planned, exists, err
+}
+
+// diffPayloads compares the fields of the desired payload with the current payload. Fields which are only part of
+// the current payload aren't compared (these aren't changed by an update), neither are fields containing secrets.
+// The order of lists is ignored.
+func diffPayloads(current []byte, desired []byte) ([]FieldChange, error) {
+	var currentFields, desiredFields map[string]any
+	if err := json.Unmarshal(current, &currentFields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(desired, &desiredFields); err != nil {
+		return nil, err
+	}
+	var result []FieldChange
+	for _, field := range slices.Sorted(maps.Keys(desiredFields)) {
+		desiredValue := desiredFields[field]
+		currentValue, ok := currentFields[field]
+		if !ok || containsSecret(desiredValue) {
+			continue
+		}
+		if !reflect.DeepEqual(normalize(currentValue), normalize(desiredValue)) {
+			result = append(result, FieldChange{Field: field, Current: currentValue, Desired: desiredValue})
+		}
+	}
+	return result, nil
+}
+
+func normalize(value any) any {
+	list, ok := value.([]any)
+	if !ok {
+		return value
+	}
+	if len(list) == 0 {
+		return nil // same as absent
+	}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		encoded, _ := json.Marshal(item)
+		result = append(result, string(encoded))
+	}
+	slices.Sort(result)
+	return result
+}
+
+func containsSecret(value any) bool {
+	encoded, _ := json.Marshal(value)
+	return strings.Contains(string(encoded), m.SecretPlaceholder)
+}
+
+func warningsOf(check m.UptimeCheck, warnings []string) []string {
+	var result []string
+	for _, warning := range warnings {
+		if strings.Contains(warning, "(id: "+check.ID+")") {
+			result = append(result, warning)
+		}
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	"github.com/PDOK/uptime-operator/internal/service/providers/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUptimeCheckService_Plan(t *testing.T) {
+	provider := mock.New()
+	service := New(WithProvider(provider))
+	existing := map[string]string{
+		m.AnnotationID:   "existing",
+		m.AnnotationName: "Existing",
+		m.AnnotationURL:  "https://pdok.example",
+	}
+	unchanged := map[string]string{
+		m.AnnotationID:   "unchanged",
+		m.AnnotationName: "Unchanged",
+		m.AnnotationURL:  "https://pdok.example",
+	}
+	service.Mutate(context.TODO(), m.CreateOrUpdate, "existing", existing, nil)
+	service.Mutate(context.TODO(), m.CreateOrUpdate, "unchanged", unchanged, nil)
+	service.Mutate(context.TODO(), m.CreateOrUpdate, "removed", map[string]string{
+		m.AnnotationID:   "orphan",
+		m.AnnotationName: "Orphan",
+		m.AnnotationURL:  "https://pdok.example",
+	}, nil)
+
+	existing[m.AnnotationInterval] = "5m"
+	plan, err := service.Plan(context.TODO(), map[string]map[string]string{
+		"ns/existing":  existing,
+		"ns/unchanged": unchanged,
+		"ns/new": {
+			m.AnnotationID:   "new",
+			m.AnnotationName: "New",
+			m.AnnotationURL:  "https://pdok.example",
+		},
+		"ns/invalid": {
+			m.AnnotationID: "invalid",
+		},
+		"ns/ignored": {
+			m.AnnotationID:     "ignored",
+			m.AnnotationIgnore: "true",
+		},
+		"ns/not-annotated": {
+			"other": "annotation",
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []PlannedCheck{{Route: "ns/new", ID: "new", Name: "New"}}, plan.Create)
+	assert.Equal(t, []PlannedCheck{{Route: "ns/existing", ID: "existing", Name: "Existing", Changes: []FieldChange{
+		{Field: "interval", Current: float64(60000000000), Desired: float64(300000000000)},
+	}}}, plan.Update)
+	assert.Equal(t, []PlannedCheck{{Route: "ns/unchanged", ID: "unchanged", Name: "Unchanged"}}, plan.Unchanged)
+	assert.Equal(t, []string{"orphan"}, plan.Orphaned)
+	if assert.Len(t, plan.Invalid, 1) {
+		assert.Equal(t, "ns/invalid", plan.Invalid[0].Route)
+	}
+}
+
+func TestDiffPayloads(t *testing.T) {
+	current := []byte(`{"name":"a","tags":["x","y"],"port":80,"auth":"user:pass","extra":true}`)
+	desired := []byte(`{"name":"b","tags":["y","x"],"port":443,"auth":"user:***","new":"value"}`)
+	changes, err := diffPayloads(current, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldChange{
+		{Field: "name", Current: "a", Desired: "b"},
+		{Field: "port", Current: float64(80), Desired: float64(443)},
+	}, changes)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/PDOK/uptime-operator/internal/model"
 )
@@ -14,3 +15,109 @@ type UptimeProvider interface {
 	// DeleteCheck deletes the given check from the uptime monitoring provider
 	DeleteCheck(ctx context.Context, check model.UptimeCheck) error
 }
+
+// MaintenanceWindowSupporter is optionally implemented by uptime monitoring providers
+// which are able to register maintenance windows natively.
+type MaintenanceWindowSupporter interface {
+	// SupportsMaintenanceWindow whether the provider itself is able to handle the given
+	// maintenance window. When false the operator pauses/resumes the check instead.
+	SupportsMaintenanceWindow(window model.MaintenanceWindow) bool
+}
+
+// MaintenanceWindowRemover is optionally implemented by uptime monitoring providers which register maintenance
+// windows separately from the check itself. Since these providers only sync the maintenance window of checks
+// having one, windows of checks which no longer have a maintenance window need to be removed explicitly.
+type MaintenanceWindowRemover interface {
+	// DeleteMaintenanceWindows removes the maintenance window(s) of the given check from the provider
+	DeleteMaintenanceWindows(ctx context.Context, check model.UptimeCheck) error
+}
+
+// ResponseAssertionSupporter is optionally implemented by uptime monitoring providers which are able
+// to apply (some of) the response assertions of a check, like expected status codes or a JSONPath.
+// Providers that don't implement this interface don't support any of these assertions.
+type ResponseAssertionSupporter interface {
+	// UnsupportedResponseAssertions returns the annotations of the response assertions of the given
+	// check which the provider can't apply. Checks with unsupported assertions are rejected as invalid.
+	UnsupportedResponseAssertions(check model.UptimeCheck) []string
+}
+
+// ThresholdApproximator is optionally implemented by uptime monitoring providers which can't apply
+// the alert thresholds of a check (response time, failures, recovery period) exactly as specified.
+type ThresholdApproximator interface {
+	// ApproximatedThresholds returns warnings about the thresholds of the given check which
+	// the provider approximates (e.g. rounds to a supported value) or ignores.
+	ApproximatedThresholds(check model.UptimeCheck) []string
+}
+
+// StringAssertionSplitter is optionally implemented by uptime monitoring providers which can't combine a
+// string-contains and a string-not-contains assertion in one check. Checks asserting both are split into two checks.
+type StringAssertionSplitter interface {
+	// MaxCheckIDLength returns the max length of check IDs the provider can tell apart, zero when unlimited
+	MaxCheckIDLength() int
+}
+
+// IntervalSupporter is optionally implemented by uptime monitoring providers which
+// only accept a specific set of intervals between checks.
+type IntervalSupporter interface {
+	// SupportedIntervals returns the intervals accepted by the provider, in ascending order
+	SupportedIntervals() []time.Duration
+}
+
+// RegionSupporter is optionally implemented by uptime monitoring providers which
+// only execute checks from a specific set of regions.
+type RegionSupporter interface {
+	// SupportedRegions returns the (lowercase) region names and aliases accepted by the provider, in ascending order
+	SupportedRegions() []string
+}
+
+// CheckLister is optionally implemented by uptime monitoring providers which are able to list
+// the checks managed by the operator. Used to remove orphaned checks when the operator starts.
+type CheckLister interface {
+	// ListCheckIDs returns the IDs (as specified in the annotations) of all checks managed by the operator.
+	// When a tag is given, only the IDs of the checks with this tag are returned.
+	ListCheckIDs(ctx context.Context, tag string) ([]string, error)
+}
+
+// DryRunner is optionally implemented by uptime monitoring providers to describe mutations of
+// checks in dry-run mode in detail, without calling mutating endpoints of the provider.
+type DryRunner interface {
+	// CheckExists whether the given check already exists with the provider. Only calls read-only endpoints.
+	CheckExists(ctx context.Context, check model.UptimeCheck) (bool, error)
+
+	// Payload returns the provider-specific payload to create (or otherwise update) the given check
+	Payload(check model.UptimeCheck, create bool) ([]byte, error)
+}
+
+// Planner is optionally implemented by uptime monitoring providers which support
+// planning mutations of checks, see UptimeCheckService.Plan.
+type Planner interface {
+	CheckLister
+	DryRunner
+
+	// CurrentPayload returns the current state of the given (existing) check with the provider, in the
+	// same form as the payload of DryRunner (as far as possible). Only calls read-only endpoints.
+	CurrentPayload(ctx context.Context, check model.UptimeCheck) ([]byte, error)
+}
+
+// Importer is optionally implemented by uptime monitoring providers which are able to import
+// checks created outside the operator, see UptimeCheckService.Import.
+type Importer interface {
+	// ListUnmanagedChecks returns all checks with the provider which aren't managed by the operator
+	ListUnmanagedChecks(ctx context.Context) ([]model.UnmanagedCheck, error)
+
+	// AdoptCheck takes ownership of the unmanaged check with the given provider ID, by tagging it as
+	// managed by the operator with the ID of the given check. Other settings of the check are left as is.
+	AdoptCheck(ctx context.Context, providerID string, check model.UptimeCheck) error
+}
+
+// Adopter is optionally implemented by uptime monitoring providers which are able to adopt an existing
+// unmanaged check for a new check, instead of creating a duplicate, see WithAdoptionPolicy.
+type Adopter interface {
+	// FindUnmanagedCheck returns the provider ID of the single check not managed by the operator which matches the
+	// given check according to the given policy. Returns an empty ID when the given check already exists with the
+	// provider, or when there's no (unambiguous) match. Only calls read-only endpoints.
+	FindUnmanagedCheck(ctx context.Context, check model.UptimeCheck, policy model.AdoptionPolicy) (string, error)
+
+	// AdoptCheck see Importer
+	AdoptCheck(ctx context.Context, providerID string, check model.UptimeCheck) error
+}
@@ -0,0 +1,29 @@
+package betterstack
+
+import "github.com/PDOK/uptime-operator/internal/model"
+
+// UnsupportedResponseAssertions Better Stack only supports expected status codes, and only
+// for monitors which don't check for a keyword as well.
+func (b *BetterStack) UnsupportedResponseAssertions(check model.UptimeCheck) []string {
+	var result []string
+	if len(check.ExpectedStatusCodes) > 0 && hasKeyword(check) {
+		result = append(result, model.AnnotationExpectedStatusCodes)
+	}
+	if check.StringMatchesRegex != "" {
+		result = append(result, model.AnnotationStringMatchesRegex)
+	}
+	if check.JSONPath != nil {
+		result = append(result, model.AnnotationJSONPath)
+	}
+	return result
+}
+
+func hasKeyword(check model.UptimeCheck) bool {
+	return check.StringContains != "" || check.StringNotContains != ""
+}
+
+// MaxCheckIDLength Better Stack can't combine a keyword and a keyword absence check in one monitor, so checks
+// asserting both are split. The ID of a check is stored as metadata, which has no practical length limit.
+func (b *BetterStack) MaxCheckIDLength() int {
+	return 0
+}
@@ -2,9 +2,12 @@ package betterstack
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	classiclog "log"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -18,10 +21,12 @@ const betterStackBaseURL = "https://uptime.betterstack.com"
 type Settings struct {
 	APIToken string
 	PageSize int
+	Timeout  time.Duration // of a single API call, including retries
 }
 
 type BetterStack struct {
 	client Client
+	index  *p.IDIndex
 }
 
 // New creates a BetterStack
@@ -32,52 +37,80 @@ func New(settings Settings) *BetterStack {
 	if settings.PageSize < 1 {
 		settings.PageSize = 50 // default https://betterstack.com/docs/uptime/api/pagination/
 	}
-	return &BetterStack{
-		Client{
-			httpClient: &http.Client{Timeout: time.Duration(5) * time.Minute},
-			settings:   settings,
+	if settings.Timeout <= 0 {
+		settings.Timeout = p.DefaultTimeout
+	}
+	b := &BetterStack{
+		client: Client{
+			httpClient: &http.Client{
+				Timeout:   settings.Timeout,
+				Transport: p.NewTransport(nil), // Better Stack signals rate limiting by HTTP 429
+			},
+			settings: settings,
 		},
 	}
+	b.index = p.NewIDIndex(b.listChecks)
+	return b
 }
 
 // CreateOrUpdateCheck create the given check with Better Stack, or update an existing check. Needs to be idempotent!
 func (b *BetterStack) CreateOrUpdateCheck(ctx context.Context, check model.UptimeCheck) (err error) {
-	existingCheckID, err := b.findCheck(check)
+	existingCheckID, err := b.findCheck(ctx, check)
 	if err != nil {
 		return fmt.Errorf("failed to find check %s, error: %w", check.ID, err)
 	}
-	if existingCheckID == p.CheckNotFound { //nolint:nestif // clean enough
-		log.FromContext(ctx).Info("creating check", "check", check)
-		monitorID, err := b.client.createMonitor(check)
-		if err != nil {
-			return fmt.Errorf("failed to create monitor for check %s, error: %w", check.ID, err)
-		}
-		if err = b.client.createMetadata(check.ID, monitorID, check.Tags); err != nil {
-			return fmt.Errorf("failed to create metadata for check %s, error: %w", check.ID, err)
-		}
-	} else {
-		log.FromContext(ctx).Info("updating check", "check", check, "betterstack ID", existingCheckID)
-		existingMonitor, err := b.client.getMonitor(existingCheckID)
-		if err != nil {
-			return fmt.Errorf("failed to get monitor for check %s, error: %w", check.ID, err)
+	if existingCheckID != p.CheckNotFound {
+		err = b.updateCheck(ctx, existingCheckID, check)
+		if !errors.Is(err, p.ErrNotFound) {
+			return err
 		}
-		if err = b.client.updateMonitor(check, existingMonitor); err != nil {
-			return fmt.Errorf("failed to update monitor for check %s (betterstack ID: %d), "+
-				"error: %w", check.ID, existingCheckID, err)
+		// monitor was deleted outside the operator, the index is out-of-sync
+		b.index.Invalidate()
+		if existingCheckID, err = b.findCheck(ctx, check); err != nil {
+			return fmt.Errorf("failed to find check %s, error: %w", check.ID, err)
 		}
-		if err = b.client.updateMetadata(check.ID, existingCheckID, check.Tags); err != nil {
-			return fmt.Errorf("failed to update metdata for check %s (betterstack ID: %d), "+
-				"error: %w", check.ID, existingCheckID, err)
+		if existingCheckID != p.CheckNotFound {
+			return b.updateCheck(ctx, existingCheckID, check)
 		}
 	}
-	return err
+	return b.createCheck(ctx, check)
+}
+
+func (b *BetterStack) createCheck(ctx context.Context, check model.UptimeCheck) error {
+	log.FromContext(ctx).Info("creating check", "check", check)
+	monitorID, err := b.client.createMonitor(ctx, check)
+	if err != nil {
+		return fmt.Errorf("failed to create monitor for check %s, error: %w", check.ID, err)
+	}
+	if err = b.client.createMetadata(ctx, check.ID, monitorID, check.Tags); err != nil {
+		return fmt.Errorf("failed to create metadata for check %s, error: %w", check.ID, err)
+	}
+	b.index.Set(check.ID, monitorID)
+	return nil
+}
+
+func (b *BetterStack) updateCheck(ctx context.Context, existingCheckID int64, check model.UptimeCheck) error {
+	log.FromContext(ctx).Info("updating check", "check", check, "betterstack ID", existingCheckID)
+	existingMonitor, err := b.client.getMonitor(ctx, existingCheckID)
+	if err != nil {
+		return fmt.Errorf("failed to get monitor for check %s, error: %w", check.ID, err)
+	}
+	if err = b.client.updateMonitor(ctx, check, existingMonitor); err != nil {
+		return fmt.Errorf("failed to update monitor for check %s (betterstack ID: %d), "+
+			"error: %w", check.ID, existingCheckID, err)
+	}
+	if err = b.client.updateMetadata(ctx, check.ID, existingCheckID, check.Tags); err != nil {
+		return fmt.Errorf("failed to update metdata for check %s (betterstack ID: %d), "+
+			"error: %w", check.ID, existingCheckID, err)
+	}
+	return nil
 }
 
 // DeleteCheck deletes the given check from Better Stack
 func (b *BetterStack) DeleteCheck(ctx context.Context, check model.UptimeCheck) error {
 	log.FromContext(ctx).Info("deleting check", "check", check)
 
-	existingCheckID, err := b.findCheck(check)
+	existingCheckID, err := b.findCheck(ctx, check)
 	if err != nil {
 		return fmt.Errorf("failed to find check %s, error: %w", check.ID, err)
 	}
@@ -85,40 +118,69 @@ func (b *BetterStack) DeleteCheck(ctx context.Context, check model.UptimeCheck)
 		log.FromContext(ctx).Info(fmt.Sprintf("check with ID '%s' is already deleted", check.ID))
 		return nil
 	}
-	if err = b.client.deleteMetadata(check.ID, existingCheckID); err != nil {
+	if err = b.client.deleteMetadata(ctx, check.ID, existingCheckID); err != nil && !errors.Is(err, p.ErrNotFound) {
 		return fmt.Errorf("failed to delete metadata for check %s (betterstack ID: %d), "+
 			"error: %w", check.ID, existingCheckID, err)
 	}
-	if err = b.client.deleteMonitor(existingCheckID); err != nil {
+	if err = b.client.deleteMonitor(ctx, existingCheckID); err != nil && !errors.Is(err, p.ErrNotFound) {
 		return fmt.Errorf("failed to delete monitor for check %s (betterstack ID: %d), "+
 			"error: %w", check.ID, existingCheckID, err)
 	}
+	b.index.Delete(check.ID)
 	return nil
 }
 
-func (b *BetterStack) findCheck(check model.UptimeCheck) (int64, error) {
-	result := p.CheckNotFound
-	metadata, err := b.client.listMetadata()
+// findCheck returns the Better Stack monitor ID of the given check, or CheckNotFound when there's no such check
+func (b *BetterStack) findCheck(ctx context.Context, check model.UptimeCheck) (int64, error) {
+	return b.index.Get(ctx, check.ID)
+}
+
+// ListCheckIDs returns the IDs of all checks at Better Stack managed by the operator (with the given tag)
+func (b *BetterStack) ListCheckIDs(ctx context.Context, tag string) ([]string, error) {
+	if tag == "" {
+		return b.index.CheckIDs(ctx)
+	}
+	checks, err := b.listChecksWithTag(ctx, tag)
+	return slices.Collect(maps.Keys(checks)), err
+}
+
+// listChecks returns the Better Stack monitor IDs of all checks managed by the operator, by
+// check ID (stored as metadata key, the metadata values contain the tags of the check)
+func (b *BetterStack) listChecks(ctx context.Context) (map[string]int64, error) {
+	return b.listChecksWithTag(ctx, model.TagManagedBy)
+}
+
+// listChecksWithTag returns the Better Stack monitor IDs of the checks managed by the operator with the given tag
+func (b *BetterStack) listChecksWithTag(ctx context.Context, tag string) (map[string]int64, error) {
+	result := make(map[string]int64)
+	metadata, err := b.client.listMetadata(ctx)
 	if err != nil {
-		return result, err
+		return nil, err
 	}
 	for {
 		for _, md := range metadata.Data {
-			if md.Attributes != nil && md.Attributes.Key == check.ID {
-				result, err = strconv.ParseInt(md.Attributes.OwnerID, 10, 64)
-				if err != nil {
-					return result, fmt.Errorf("failed to parse monitor ID %s to integer", md.Attributes.OwnerID)
-				}
-				return result, nil
+			if md.Attributes == nil || !slices.ContainsFunc(md.Attributes.Values, isManagedByTag) ||
+				!slices.ContainsFunc(md.Attributes.Values, func(value MetadataResponseValue) bool { return value.Value == tag }) {
+				continue
 			}
+			monitorID, err := strconv.ParseInt(md.Attributes.OwnerID, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse monitor ID %s to integer", md.Attributes.OwnerID)
+			}
+			result[md.Attributes.Key] = monitorID
 		}
 		if !metadata.HasNext() {
 			break // exit infinite loop
 		}
-		metadata, err = metadata.Next(b.client)
+		metadata, err = metadata.Next(ctx, b.client)
 		if err != nil {
-			return result, err
+			return nil, err
 		}
 	}
+	log.FromContext(ctx).Info("listed existing checks", "count", len(result))
 	return result, nil
 }
+
+func isManagedByTag(value MetadataResponseValue) bool {
+	return value.Value == model.TagManagedBy
+}
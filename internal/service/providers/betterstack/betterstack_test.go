@@ -147,7 +147,7 @@ func runIntegrationTest(t *testing.T, tt struct {
 
 		// create/update/delete actual check with REAL Better Stack API.
 		m := New(settings)
-		check, err := model.NewUptimeCheck("foo", tt.annotations)
+		check, err := model.NewUptimeCheck(context.TODO(), "foo", tt.annotations, nil)
 		assert.NoError(t, err)
 		if tt.wantDelete {
 			if err := m.DeleteCheck(context.TODO(), *check); (err != nil) != tt.wantErr {
@@ -156,7 +156,7 @@ func runIntegrationTest(t *testing.T, tt struct {
 			// give Better Stack some time to process the api call, just in case
 			time.Sleep(5 * time.Second)
 
-			existingCheckID, err := m.findCheck(*check)
+			existingCheckID, err := m.findCheck(context.TODO(), *check)
 			assert.NoError(t, err)
 			assert.Equal(t, providers.CheckNotFound, existingCheckID)
 		} else {
@@ -168,3 +168,85 @@ func runIntegrationTest(t *testing.T, tt struct {
 		}
 	})
 }
+
+func TestCheckToMonitor(t *testing.T) {
+	tests := []struct {
+		name            string
+		check           model.UptimeCheck
+		wantType        string
+		wantURL         string
+		wantPort        int
+		wantMethod      string
+		wantRequestBody string
+		wantKeyword     string
+		wantErr         bool
+	}{
+		{
+			name:     "HTTP check",
+			check:    model.UptimeCheck{Type: model.CheckTypeHTTP, URL: "https://pdok.example/path"},
+			wantType: "status",
+			wantURL:  "https://pdok.example/path",
+			wantPort: 443,
+		},
+		{
+			name:     "HTTP check with expected status codes",
+			check:    model.UptimeCheck{Type: model.CheckTypeHTTP, URL: "https://pdok.example/path", ExpectedStatusCodes: []int{200, 204}},
+			wantType: "expected_status_code",
+			wantURL:  "https://pdok.example/path",
+			wantPort: 443,
+		},
+		{
+			name:            "HTTP check with POST request",
+			check:           model.UptimeCheck{Type: model.CheckTypeHTTP, URL: "https://pdok.example/path", HTTPMethod: "POST", RequestBody: "request=GetRecords"},
+			wantType:        "status",
+			wantURL:         "https://pdok.example/path",
+			wantPort:        443,
+			wantMethod:      "post",
+			wantRequestBody: "request=GetRecords",
+		},
+		{
+			name:     "TCP check",
+			check:    model.UptimeCheck{Type: model.CheckTypeTCP, URL: "tcp://pdok.example:5432"},
+			wantType: "tcp",
+			wantURL:  "pdok.example",
+			wantPort: 5432,
+		},
+		{
+			name:    "TCP check with string to send",
+			check:   model.UptimeCheck{Type: model.CheckTypeTCP, URL: "tcp://pdok.example:5432", TCPStringToSend: "PING"},
+			wantErr: true,
+		},
+		{
+			name:     "Ping check",
+			check:    model.UptimeCheck{Type: model.CheckTypePing, URL: "ping://pdok.example"},
+			wantType: "ping",
+			wantURL:  "pdok.example",
+		},
+		{
+			name:            "DNS check",
+			check:           model.UptimeCheck{Type: model.CheckTypeDNS, URL: "dns://pdok.example", DNSExpectedIP: "192.0.2.1", DNSNameserver: "ns1.example"},
+			wantType:        "dns",
+			wantURL:         "ns1.example",
+			wantRequestBody: "pdok.example",
+			wantKeyword:     "192.0.2.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := checkToMonitor(tt.check)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantType, result.MonitorType)
+			assert.Equal(t, tt.wantURL, result.URL)
+			assert.Equal(t, tt.wantPort, result.Port)
+			assert.Equal(t, tt.wantRequestBody, result.RequestBody)
+			if tt.wantMethod != "" {
+				assert.Equal(t, tt.wantMethod, result.HTTPMethod)
+			}
+			assert.Equal(t, tt.wantKeyword, result.RequiredKeyword)
+		})
+	}
+}
@@ -2,11 +2,14 @@ package betterstack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/PDOK/uptime-operator/internal/model"
 	p "github.com/PDOK/uptime-operator/internal/service/providers"
@@ -29,6 +32,10 @@ func (h Client) execRequest(req *http.Request, expectedStatus int) (*http.Respon
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == http.StatusNotFound && expectedStatus != http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("got status %d, expected %d: %w", resp.StatusCode, expectedStatus, p.ErrNotFound)
+	}
 	if resp.StatusCode != expectedStatus {
 		defer resp.Body.Close()
 		result, _ := io.ReadAll(resp.Body)
@@ -46,19 +53,21 @@ func (h Client) execRequestIgnoreResponseBody(req *http.Request, expectedStatus
 	return nil
 }
 
+type MetadataResponseValue struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
 type MetadataListResponse struct {
 	Data []struct {
 		ID         string `json:"id"`
 		Type       string `json:"type"`
 		Attributes *struct {
-			Key    string `json:"key"`
-			Values []struct {
-				Type  string `json:"type"`
-				Value string `json:"value"`
-			} `json:"values"`
-			TeamName  string `json:"team_name"`
-			OwnerID   string `json:"owner_id"`
-			OwnerType string `json:"owner_type"`
+			Key       string                  `json:"key"`
+			Values    []MetadataResponseValue `json:"values"`
+			TeamName  string                  `json:"team_name"`
+			OwnerID   string                  `json:"owner_id"`
+			OwnerType string                  `json:"owner_type"`
 		} `json:"attributes"`
 	} `json:"data"`
 	Pagination *struct {
@@ -70,9 +79,9 @@ type MetadataListResponse struct {
 }
 
 // listMetadata https://betterstack.com/docs/uptime/api/list-all-existing-metadata/
-func (h Client) listMetadata() (*MetadataListResponse, error) {
-	url := fmt.Sprintf("%s/api/v3/metadata?owner_type=Monitor&per_page=%d", betterStackBaseURL, h.settings.PageSize)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (h Client) listMetadata(ctx context.Context) (*MetadataListResponse, error) {
+	listURL := fmt.Sprintf("%s/api/v3/metadata?owner_type=Monitor&per_page=%d", betterStackBaseURL, h.settings.PageSize)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -98,13 +107,13 @@ func (m MetadataListResponse) HasNext() bool {
 }
 
 // Next paginate though metadata, see https://betterstack.com/docs/uptime/api/pagination/
-func (m MetadataListResponse) Next(client Client) (*MetadataListResponse, error) {
+func (m MetadataListResponse) Next(ctx context.Context, client Client) (*MetadataListResponse, error) {
 	if !m.HasNext() {
 		return nil, nil
 	}
 
 	// Make HTTP request to the next URL
-	req, err := http.NewRequest(http.MethodGet, m.Pagination.Next, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.Pagination.Next, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +144,7 @@ type MetadataUpdateRequest struct {
 }
 
 // createMetadata https://betterstack.com/docs/uptime/api/update-an-existing-metadata-record/
-func (h Client) createMetadata(key string, monitorID int64, tags []string) error {
+func (h Client) createMetadata(ctx context.Context, key string, monitorID int64, tags []string) error {
 	metadataUpdateRequest := MetadataUpdateRequest{
 		Key:       key,
 		OwnerID:   strconv.FormatInt(monitorID, 10),
@@ -149,7 +158,7 @@ func (h Client) createMetadata(key string, monitorID int64, tags []string) error
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPost, betterStackBaseURL+"/api/v3/metadata", body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, betterStackBaseURL+"/api/v3/metadata", body)
 	if err != nil {
 		return err
 	}
@@ -160,7 +169,7 @@ func (h Client) createMetadata(key string, monitorID int64, tags []string) error
 }
 
 // updateMetadata https://betterstack.com/docs/uptime/api/update-an-existing-metadata-record/
-func (h Client) updateMetadata(key string, monitorID int64, tags []string) error {
+func (h Client) updateMetadata(ctx context.Context, key string, monitorID int64, tags []string) error {
 	metadataUpdateRequest := MetadataUpdateRequest{
 		Key:       key,
 		OwnerID:   strconv.FormatInt(monitorID, 10),
@@ -174,7 +183,7 @@ func (h Client) updateMetadata(key string, monitorID int64, tags []string) error
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPost, betterStackBaseURL+"/api/v3/metadata", body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, betterStackBaseURL+"/api/v3/metadata", body)
 	if err != nil {
 		return err
 	}
@@ -185,7 +194,7 @@ func (h Client) updateMetadata(key string, monitorID int64, tags []string) error
 }
 
 // deleteMetadata https://betterstack.com/docs/uptime/api/update-an-existing-metadata-record/
-func (h Client) deleteMetadata(key string, monitorID int64) error {
+func (h Client) deleteMetadata(ctx context.Context, key string, monitorID int64) error {
 	metadataDeleteRequest := MetadataUpdateRequest{
 		Key:       key,
 		OwnerID:   strconv.FormatInt(monitorID, 10),
@@ -197,7 +206,7 @@ func (h Client) deleteMetadata(key string, monitorID int64) error {
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPost, betterStackBaseURL+"/api/v3/metadata", body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, betterStackBaseURL+"/api/v3/metadata", body)
 	if err != nil {
 		return err
 	}
@@ -215,16 +224,33 @@ type MonitorRequestHeader struct {
 }
 
 type MonitorCreateOrUpdateRequest struct {
-	MonitorType       string                 `json:"monitor_type"`
-	URL               string                 `json:"url"`
-	PronounceableName string                 `json:"pronounceable_name"`
-	Port              int                    `json:"port"`
-	Email             bool                   `json:"email"`
-	Sms               bool                   `json:"sms"`
-	Call              bool                   `json:"call"`
-	RequiredKeyword   string                 `json:"required_keyword"`
-	CheckFrequency    int                    `json:"check_frequency"`
-	RequestHeaders    []MonitorRequestHeader `json:"request_headers"`
+	MonitorType         string                 `json:"monitor_type"`
+	URL                 string                 `json:"url"`
+	PronounceableName   string                 `json:"pronounceable_name"`
+	Port                int                    `json:"port"`
+	Email               bool                   `json:"email"`
+	Sms                 bool                   `json:"sms"`
+	Call                bool                   `json:"call"`
+	RequiredKeyword     string                 `json:"required_keyword"`
+	HTTPMethod          string                 `json:"http_method,omitempty"`
+	RequestBody         string                 `json:"request_body"`
+	AuthUsername        string                 `json:"auth_username"`
+	AuthPassword        string                 `json:"auth_password"`
+	ExpectedStatusCodes []int                  `json:"expected_status_codes,omitempty"`
+	CheckFrequency      int                    `json:"check_frequency"`
+	Regions             []string               `json:"regions"`
+	RequestTimeout      *int                   `json:"request_timeout,omitempty"`
+	ConfirmationPeriod  *int                   `json:"confirmation_period,omitempty"`
+	RecoveryPeriod      *int                   `json:"recovery_period,omitempty"`
+	RequestHeaders      []MonitorRequestHeader `json:"request_headers"`
+	Paused              bool                   `json:"paused"`
+	VerifySSL           bool                   `json:"verify_ssl"`
+	SSLExpiration       *int                   `json:"ssl_expiration"` // null disables the TLS expiry check
+	// maintenance window fields are explicitly nullable, since null removes the window
+	MaintenanceFrom     *string  `json:"maintenance_from"`
+	MaintenanceTo       *string  `json:"maintenance_to"`
+	MaintenanceTimezone string   `json:"maintenance_timezone,omitempty"`
+	MaintenanceDays     []string `json:"maintenance_days,omitempty"`
 }
 
 type MonitorCreateResponse struct {
@@ -235,15 +261,18 @@ type MonitorCreateResponse struct {
 }
 
 // createMonitor https://betterstack.com/docs/uptime/api/create-a-new-monitor/
-func (h Client) createMonitor(check model.UptimeCheck) (int64, error) {
-	createRequest := checkToMonitor(check)
+func (h Client) createMonitor(ctx context.Context, check model.UptimeCheck) (int64, error) {
+	createRequest, err := checkToMonitor(check)
+	if err != nil {
+		return -1, err
+	}
 
 	body := &bytes.Buffer{}
-	err := json.NewEncoder(body).Encode(createRequest)
+	err = json.NewEncoder(body).Encode(createRequest)
 	if err != nil {
 		return -1, err
 	}
-	req, err := http.NewRequest(http.MethodPost, betterStackBaseURL+"/api/v2/monitors", body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, betterStackBaseURL+"/api/v2/monitors", body)
 	if err != nil {
 		return -1, err
 	}
@@ -266,8 +295,11 @@ func (h Client) createMonitor(check model.UptimeCheck) (int64, error) {
 }
 
 // updateMonitor https://betterstack.com/docs/uptime/api/update-an-existing-monitor/
-func (h Client) updateMonitor(check model.UptimeCheck, existingMonitor *MonitorGetResponse) error {
-	updateRequest := checkToMonitor(check)
+func (h Client) updateMonitor(ctx context.Context, check model.UptimeCheck, existingMonitor *MonitorGetResponse) error {
+	updateRequest, err := checkToMonitor(check)
+	if err != nil {
+		return err
+	}
 
 	if existingMonitor == nil || existingMonitor.Data == nil || existingMonitor.Data.Attributes == nil {
 		return fmt.Errorf("invalid monitor response, expected values are nil: %v", existingMonitor)
@@ -280,11 +312,11 @@ func (h Client) updateMonitor(check model.UptimeCheck, existingMonitor *MonitorG
 		})
 	}
 	body := &bytes.Buffer{}
-	err := json.NewEncoder(body).Encode(&updateRequest)
+	err = json.NewEncoder(body).Encode(&updateRequest)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v2/monitors/%s", betterStackBaseURL, existingMonitor.Data.ID), body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("%s/api/v2/monitors/%s", betterStackBaseURL, existingMonitor.Data.ID), body)
 	if err != nil {
 		return err
 	}
@@ -295,8 +327,8 @@ func (h Client) updateMonitor(check model.UptimeCheck, existingMonitor *MonitorG
 }
 
 // deleteMonitor https://betterstack.com/docs/uptime/api/delete-an-existing-monitor/
-func (h Client) deleteMonitor(monitorID int64) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/monitors/%d", betterStackBaseURL, monitorID), nil)
+func (h Client) deleteMonitor(ctx context.Context, monitorID int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/monitors/%d", betterStackBaseURL, monitorID), nil)
 	if err != nil {
 		return err
 	}
@@ -311,18 +343,27 @@ type MonitorGetResponse struct {
 		ID         string `json:"id"`
 		Type       string `json:"type"`
 		Attributes *struct {
-			URL               string                 `json:"url"`
-			PronounceableName string                 `json:"pronounceable_name"`
-			MonitorType       string                 `json:"monitor_type"`
-			RequiredKeyword   string                 `json:"required_keyword"`
-			CheckFrequency    int                    `json:"check_frequency"`
-			RequestHeaders    []MonitorRequestHeader `json:"request_headers"`
+			URL                 string                 `json:"url"`
+			PronounceableName   string                 `json:"pronounceable_name"`
+			MonitorType         string                 `json:"monitor_type"`
+			RequiredKeyword     string                 `json:"required_keyword"`
+			HTTPMethod          string                 `json:"http_method,omitempty"`
+			RequestBody         string                 `json:"request_body"`
+			AuthUsername        string                 `json:"auth_username"`
+			AuthPassword        string                 `json:"auth_password"`
+			ExpectedStatusCodes []int                  `json:"expected_status_codes,omitempty"`
+			CheckFrequency      int                    `json:"check_frequency"`
+			Regions             []string               `json:"regions"`
+			RequestTimeout      *int                   `json:"request_timeout,omitempty"`
+			ConfirmationPeriod  *int                   `json:"confirmation_period,omitempty"`
+			RecoveryPeriod      *int                   `json:"recovery_period,omitempty"`
+			RequestHeaders      []MonitorRequestHeader `json:"request_headers"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
 
-func (h Client) getMonitor(monitorID int64) (*MonitorGetResponse, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/monitors/%d", betterStackBaseURL, monitorID), nil)
+func (h Client) getMonitor(ctx context.Context, monitorID int64) (*MonitorGetResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/monitors/%d", betterStackBaseURL, monitorID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +382,57 @@ func (h Client) getMonitor(monitorID int64) (*MonitorGetResponse, error) {
 	return existingMonitor, nil
 }
 
-func checkToMonitor(check model.UptimeCheck) MonitorCreateOrUpdateRequest {
+func checkToMonitor(check model.UptimeCheck) (MonitorCreateOrUpdateRequest, error) {
+	checkURL, err := url.ParseRequestURI(check.URL)
+	if err != nil {
+		return MonitorCreateOrUpdateRequest{}, err
+	}
+	var request MonitorCreateOrUpdateRequest
+	switch check.Type {
+	case model.CheckTypeTCP:
+		request, err = tcpToMonitor(checkURL, check)
+	case model.CheckTypePing:
+		request = MonitorCreateOrUpdateRequest{
+			MonitorType: "ping",
+			URL:         checkURL.Hostname(),
+		}
+	case model.CheckTypeDNS:
+		// Better Stack queries the nameserver (url) for the hostname (request body) and
+		// expects the answer to contain the keyword
+		request = MonitorCreateOrUpdateRequest{
+			MonitorType:     "dns",
+			URL:             check.DNSNameserver,
+			RequestBody:     checkURL.Hostname(),
+			RequiredKeyword: check.DNSExpectedIP,
+		}
+	default:
+		request, err = httpToMonitor(checkURL, check)
+	}
+	if err != nil {
+		return request, err
+	}
+	if request.Regions, err = toRegions(check.Regions); err != nil {
+		return request, err
+	}
+	request.PronounceableName = check.Name
+	request.CheckFrequency = toSupportedInterval(check.Interval)
+	setThresholds(&request, check) // approximations are reported by ApproximatedThresholds
+	request.Email = false
+	request.Sms = false
+	request.Call = false
+	request.Paused = check.Paused
+	if check.Maintenance != nil {
+		if window, ok := toDailyMaintenanceWindow(*check.Maintenance); ok {
+			request.MaintenanceFrom = &window.From
+			request.MaintenanceTo = &window.To
+			request.MaintenanceTimezone = "UTC"
+			request.MaintenanceDays = window.Days
+		}
+	}
+	return request, nil
+}
+
+func httpToMonitor(checkURL *url.URL, check model.UptimeCheck) (MonitorCreateOrUpdateRequest, error) {
 	var request MonitorCreateOrUpdateRequest
 	switch {
 	case check.StringContains != "":
@@ -354,23 +445,63 @@ func checkToMonitor(check model.UptimeCheck) MonitorCreateOrUpdateRequest {
 			MonitorType:     "keyword_absence",
 			RequiredKeyword: check.StringNotContains,
 		}
+	case len(check.ExpectedStatusCodes) > 0:
+		request = MonitorCreateOrUpdateRequest{
+			MonitorType:         "expected_status_code",
+			ExpectedStatusCodes: check.ExpectedStatusCodes,
+		}
 	default:
 		request = MonitorCreateOrUpdateRequest{
 			MonitorType: "status",
 		}
 	}
+	port, err := model.GetPort(checkURL)
+	if err != nil {
+		return request, err
+	}
 	request.URL = check.URL
-	request.PronounceableName = check.Name
-	request.Port = 443
-	request.CheckFrequency = toSupportedInterval(check.Interval)
-	request.Email = false
-	request.Sms = false
-	request.Call = false
-	for name, value := range check.RequestHeaders {
+	request.Port = port
+	request.HTTPMethod = strings.ToLower(check.HTTPMethod)
+	if request.HTTPMethod == "" {
+		request.HTTPMethod = "get" // explicitly, to reset the method of an existing monitor
+	}
+	request.RequestBody = check.RequestBody.Reveal()
+	if check.BasicAuth != nil {
+		request.AuthUsername = check.BasicAuth.Username
+		request.AuthPassword = check.BasicAuth.Password.Reveal()
+	}
+	request.VerifySSL = p.IsHTTPS(checkURL)
+	if check.TLSExpiryAlertDays > 0 {
+		sslExpiration := toSupportedSSLExpiration(check.TLSExpiryAlertDays)
+		request.SSLExpiration = &sslExpiration
+	}
+	for name, value := range check.RevealRequestHeaders() {
 		request.RequestHeaders = append(request.RequestHeaders, MonitorRequestHeader{
 			Name:  name,
 			Value: value,
 		})
 	}
-	return request
+	return request, nil
+}
+
+func tcpToMonitor(checkURL *url.URL, check model.UptimeCheck) (MonitorCreateOrUpdateRequest, error) {
+	if check.TCPStringToSend != "" || check.TCPStringToExpect != "" {
+		return MonitorCreateOrUpdateRequest{}, fmt.Errorf("sending or expecting a string isn't supported by Better Stack "+
+			"in TCP checks, remove the %s and %s annotations", model.AnnotationTCPStringToSend, model.AnnotationTCPStringToExpect)
+	}
+	port, err := model.GetPort(checkURL)
+	if err != nil {
+		return MonitorCreateOrUpdateRequest{}, err
+	}
+	return MonitorCreateOrUpdateRequest{
+		MonitorType: "tcp",
+		URL:         checkURL.Hostname(),
+		Port:        port,
+	}, nil
+}
+
+// toSupportedSSLExpiration Better Stack only accepts a specific set of days, use the first
+// supported value which is at least the requested number of days (so we're never alerted too late).
+func toSupportedSSLExpiration(days int) int {
+	return roundUpToSupported(days, []int{1, 2, 3, 7, 14, 30, 60})
 }
@@ -0,0 +1,68 @@
+package betterstack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+	p "github.com/PDOK/uptime-operator/internal/service/providers"
+)
+
+// CheckExists whether the given check exists with Better Stack
+func (b *BetterStack) CheckExists(ctx context.Context, check model.UptimeCheck) (bool, error) {
+	existingCheckID, err := b.findCheck(ctx, check)
+	return existingCheckID != p.CheckNotFound, err
+}
+
+// Payload returns the JSON message to create or update the monitor of the given check with Better Stack
+func (b *BetterStack) Payload(check model.UptimeCheck, _ bool) ([]byte, error) {
+	monitor, err := checkToMonitor(check)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(monitor)
+}
+
+// CurrentPayload returns the current attributes of the monitor of the given check with Better Stack
+func (b *BetterStack) CurrentPayload(ctx context.Context, check model.UptimeCheck) ([]byte, error) {
+	existingCheckID, err := b.findCheck(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	if existingCheckID == p.CheckNotFound {
+		return nil, fmt.Errorf("check %s: %w", check.ID, p.ErrNotFound)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/monitors/%d", betterStackBaseURL, existingCheckID), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.client.execRequest(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var monitor struct {
+		Data struct {
+			Attributes map[string]any `json:"attributes"`
+		} `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&monitor); err != nil {
+		return nil, err
+	}
+	// request headers are returned with their ID, put these in the same form as in the payload
+	if headers, ok := monitor.Data.Attributes["request_headers"].([]any); ok {
+		var requestHeaders []MonitorRequestHeader
+		for _, rawHeader := range headers {
+			if header, ok := rawHeader.(map[string]any); ok {
+				name, _ := header["name"].(string)
+				value, _ := header["value"].(string)
+				requestHeaders = append(requestHeaders, MonitorRequestHeader{Name: name, Value: value})
+			}
+		}
+		monitor.Data.Attributes["request_headers"] = requestHeaders
+	}
+	return json.Marshal(monitor.Data.Attributes)
+}
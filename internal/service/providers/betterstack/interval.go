@@ -1,29 +1,22 @@
 package betterstack
 
-import "math"
+import (
+	"time"
 
-func toSupportedInterval(intervalInMin int) int {
-	// Better Stack only accepts a specific sets of intervals
-	supportedIntervals := []int{30, 45, 60, 120, 180, 300, 600, 900, 1800}
+	p "github.com/PDOK/uptime-operator/internal/service/providers"
+)
 
-	intervalInSec := intervalInMin * 60
-	if intervalInSec <= 0 {
-		return supportedIntervals[0] // use the smallest supported interval
-	}
-	if intervalInSec > supportedIntervals[len(supportedIntervals)-1] {
-		return supportedIntervals[len(supportedIntervals)-1] // use the largest supported interval
-	}
+// supportedIntervals Better Stack only accepts a specific set of intervals
+var supportedIntervals = []time.Duration{
+	30 * time.Second, 45 * time.Second, time.Minute, 2 * time.Minute, 3 * time.Minute,
+	5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+}
 
-	nearestInterval := supportedIntervals[0]
-	prevDiff := math.MaxInt
+func (b *BetterStack) SupportedIntervals() []time.Duration {
+	return supportedIntervals
+}
 
-	// use nearest supported interval
-	for _, si := range supportedIntervals {
-		diff := int(math.Abs(float64(intervalInSec - si)))
-		if diff < prevDiff {
-			prevDiff = diff
-			nearestInterval = si
-		}
-	}
-	return nearestInterval
+// toSupportedInterval returns the nearest supported interval in seconds
+func toSupportedInterval(interval time.Duration) int {
+	return int(p.NearestInterval(interval, supportedIntervals).Seconds())
 }
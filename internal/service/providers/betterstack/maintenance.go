@@ -0,0 +1,102 @@
+package betterstack
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+)
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// dailyMaintenanceWindow Better Stack only supports daily maintenance windows: a start
+// and end time (in UTC) on a set of weekdays.
+type dailyMaintenanceWindow struct {
+	From string
+	To   string
+	Days []string
+}
+
+// SupportsMaintenanceWindow Better Stack supports recurring maintenance windows which can be
+// expressed as a time of day on certain weekdays, like "30 2 * * 1-5" with a duration of 1h.
+func (b *BetterStack) SupportsMaintenanceWindow(window model.MaintenanceWindow) bool {
+	_, ok := toDailyMaintenanceWindow(window)
+	return ok
+}
+
+func toDailyMaintenanceWindow(window model.MaintenanceWindow) (*dailyMaintenanceWindow, bool) {
+	if !window.IsRecurring() {
+		return nil, false
+	}
+	fields := strings.Fields(window.Cron)
+	if len(fields) != 5 || fields[2] != "*" || fields[3] != "*" {
+		return nil, false
+	}
+	minute, err := strconv.Atoi(fields[0])
+	if err != nil || minute < 0 || minute > 59 {
+		return nil, false
+	}
+	hour, err := strconv.Atoi(fields[1])
+	if err != nil || hour < 0 || hour > 23 {
+		return nil, false
+	}
+	days, ok := toWeekdays(fields[4])
+	if !ok {
+		return nil, false
+	}
+	from := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	to := from + window.Duration
+	if to >= 24*time.Hour {
+		return nil, false // windows spanning midnight aren't supported
+	}
+	return &dailyMaintenanceWindow{
+		From: formatTimeOfDay(from),
+		To:   formatTimeOfDay(to),
+		Days: days,
+	}, true
+}
+
+// toWeekdays converts the day-of-week field of a cron expression (like "*", "1-5" or "sat,sun")
+func toWeekdays(field string) ([]string, bool) {
+	if field == "*" {
+		return weekdays, true
+	}
+	var result []string
+	for _, part := range strings.Split(field, ",") {
+		start, end, isRange := strings.Cut(part, "-")
+		first, ok := toWeekday(start)
+		if !ok {
+			return nil, false
+		}
+		last := first
+		if isRange {
+			if last, ok = toWeekday(end); !ok || last < first {
+				return nil, false
+			}
+		}
+		for day := first; day <= last; day++ {
+			if !slices.Contains(result, weekdays[day%7]) {
+				result = append(result, weekdays[day%7])
+			}
+		}
+	}
+	return result, true
+}
+
+func toWeekday(s string) (int, bool) {
+	if i := slices.Index(weekdays, strings.ToLower(s)); i >= 0 {
+		return i, true
+	}
+	day, err := strconv.Atoi(s)
+	if err != nil || day < 0 || day > 7 { // both 0 and 7 are sunday in cron
+		return -1, false
+	}
+	return day, true
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
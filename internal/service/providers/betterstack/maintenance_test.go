@@ -0,0 +1,59 @@
+package betterstack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDailyMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name   string
+		window model.MaintenanceWindow
+		want   *dailyMaintenanceWindow
+	}{
+		{
+			name:   "Every day",
+			window: model.MaintenanceWindow{Cron: "30 2 * * *", Duration: time.Hour},
+			want:   &dailyMaintenanceWindow{From: "02:30:00", To: "03:30:00", Days: weekdays},
+		},
+		{
+			name:   "Working days",
+			window: model.MaintenanceWindow{Cron: "0 22 * * 1-5", Duration: 90 * time.Minute},
+			want:   &dailyMaintenanceWindow{From: "22:00:00", To: "23:30:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}},
+		},
+		{
+			name:   "Weekend by name",
+			window: model.MaintenanceWindow{Cron: "0 6 * * sat,SUN", Duration: time.Hour},
+			want:   &dailyMaintenanceWindow{From: "06:00:00", To: "07:00:00", Days: []string{"sat", "sun"}},
+		},
+		{
+			name:   "Spanning midnight",
+			window: model.MaintenanceWindow{Cron: "0 23 * * *", Duration: 2 * time.Hour},
+		},
+		{
+			name:   "Day of month",
+			window: model.MaintenanceWindow{Cron: "0 2 1 * *", Duration: time.Hour},
+		},
+		{
+			name:   "Every 15 minutes",
+			window: model.MaintenanceWindow{Cron: "*/15 * * * *", Duration: time.Minute},
+		},
+		{
+			name: "One-off",
+			window: model.MaintenanceWindow{
+				Start: time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := toDailyMaintenanceWindow(tt.window)
+			assert.Equal(t, tt.want != nil, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
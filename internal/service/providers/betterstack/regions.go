@@ -0,0 +1,43 @@
+package betterstack
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// regions Better Stack regions, by (lowercase) region name or alias
+var regions = map[string]string{
+	"us":   "us",
+	"na":   "us",
+	"eu":   "eu",
+	"as":   "as",
+	"apac": "as",
+	"au":   "au",
+}
+
+// SupportedRegions returns the region names and aliases supported by Better Stack
+func (b *BetterStack) SupportedRegions() []string {
+	return slices.Sorted(maps.Keys(regions))
+}
+
+// toRegions converts region names to Better Stack regions. Returns all regions when no regions
+// are given, since that's the Better Stack default (and omitting regions keeps the existing ones).
+func toRegions(checkRegions []string) ([]string, error) {
+	if len(checkRegions) == 0 {
+		return []string{"us", "eu", "as", "au"}, nil
+	}
+	var result []string
+	for _, region := range checkRegions {
+		betterstackRegion, ok := regions[strings.ToLower(region)]
+		if !ok {
+			return nil, fmt.Errorf("region '%s' isn't supported by Better Stack, should be one of %v",
+				region, slices.Sorted(maps.Keys(regions)))
+		}
+		if !slices.Contains(result, betterstackRegion) {
+			result = append(result, betterstackRegion)
+		}
+	}
+	return result, nil
+}
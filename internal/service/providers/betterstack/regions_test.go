@@ -0,0 +1,43 @@
+package betterstack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		regions []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "No regions",
+			regions: nil,
+			want:    []string{"us", "eu", "as", "au"},
+		},
+		{
+			name:    "Regions with aliases",
+			regions: []string{"eu", "apac", "as"},
+			want:    []string{"eu", "as"},
+		},
+		{
+			name:    "Unsupported region",
+			regions: []string{"latam"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toRegions(tt.regions)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package betterstack
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+)
+
+// Better Stack only accepts specific sets of values (in seconds)
+var (
+	supportedRequestTimeouts     = []int{2, 3, 5, 10, 15, 30, 45, 60}
+	supportedConfirmationPeriods = []int{0, 30, 60, 120, 180, 300, 600}
+	supportedRecoveryPeriods     = []int{0, 60, 180, 300, 900, 1800, 3600, 7200, 10800, 21600, 43200, 86400}
+)
+
+// ApproximatedThresholds returns warnings about the thresholds of the given check which
+// Better Stack can't apply exactly as specified, see setThresholds.
+func (b *BetterStack) ApproximatedThresholds(check model.UptimeCheck) []string {
+	request := MonitorCreateOrUpdateRequest{CheckFrequency: toSupportedInterval(check.Interval)}
+	return setThresholds(&request, check)
+}
+
+// setThresholds maps the thresholds of the check to Better Stack. Thresholds which aren't
+// specified are omitted, so Better Stack uses its defaults (or the existing values). Returns
+// warnings about thresholds which are approximated.
+func setThresholds(request *MonitorCreateOrUpdateRequest, check model.UptimeCheck) (warnings []string) {
+	isHTTP := check.Type == "" || check.Type == model.CheckTypeHTTP
+	if check.ResponseTimeThreshold > 0 && isHTTP {
+		// Better Stack has no response time alert, but an HTTP request exceeding the timeout is considered down
+		requestTimeout := roundUpToSupported(int(math.Ceil(check.ResponseTimeThreshold.Seconds())), supportedRequestTimeouts)
+		request.RequestTimeout = &requestTimeout
+		warnings = append(warnings, fmt.Sprintf("%s %s of uptime check '%s' (id: %s) is applied as a request timeout "+
+			"of %s by the uptime provider, slower responses are considered down.", model.AnnotationResponseTimeThreshold,
+			check.ResponseTimeThreshold, check.Name, check.ID, seconds(requestTimeout)))
+	}
+	if check.AlertAfterFailures > 0 {
+		// Better Stack waits for the confirmation period after the first failure before alerting
+		wanted := (check.AlertAfterFailures - 1) * request.CheckFrequency
+		confirmationPeriod := roundUpToSupported(wanted, supportedConfirmationPeriods)
+		request.ConfirmationPeriod = &confirmationPeriod
+		if confirmationPeriod != wanted {
+			warnings = append(warnings, fmt.Sprintf("%s %d of uptime check '%s' (id: %s) is applied as a confirmation "+
+				"period of %s by the uptime provider, instead of %s.", model.AnnotationAlertAfterFailures,
+				check.AlertAfterFailures, check.Name, check.ID, seconds(confirmationPeriod), seconds(wanted)))
+		}
+	}
+	if check.RecoveryPeriod > 0 {
+		wanted := int(math.Ceil(check.RecoveryPeriod.Seconds()))
+		recoveryPeriod := roundUpToSupported(wanted, supportedRecoveryPeriods)
+		request.RecoveryPeriod = &recoveryPeriod
+		if recoveryPeriod != wanted {
+			warnings = append(warnings, fmt.Sprintf("%s %s of uptime check '%s' (id: %s) isn't supported by the uptime "+
+				"provider, using %s instead.", model.AnnotationRecoveryPeriod, check.RecoveryPeriod, check.Name, check.ID,
+				seconds(recoveryPeriod)))
+		}
+	}
+	return warnings
+}
+
+func seconds(s int) time.Duration {
+	return time.Duration(s) * time.Second
+}
+
+// roundUpToSupported returns the first supported value which is at least the given value, or
+// otherwise the largest supported value. The supported values should be sorted ascending.
+func roundUpToSupported(value int, supported []int) int {
+	for _, s := range supported {
+		if s >= value {
+			return s
+		}
+	}
+	return supported[len(supported)-1]
+}
@@ -0,0 +1,69 @@
+package betterstack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetThresholds(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	tests := []struct {
+		name                   string
+		check                  model.UptimeCheck
+		wantRequestTimeout     *int
+		wantConfirmationPeriod *int
+		wantRecoveryPeriod     *int
+		wantWarnings           int
+	}{
+		{
+			name:  "No thresholds",
+			check: model.UptimeCheck{},
+		},
+		{
+			name: "Thresholds rounded up to supported values",
+			check: model.UptimeCheck{
+				ResponseTimeThreshold: 4 * time.Second,
+				AlertAfterFailures:    3,
+				RecoveryPeriod:        2 * time.Minute,
+			},
+			wantRequestTimeout:     intPtr(5),
+			wantConfirmationPeriod: intPtr(120), // 2 failures after the first one, each 60 seconds apart
+			wantRecoveryPeriod:     intPtr(180),
+			wantWarnings:           2, // request timeout and recovery period
+		},
+		{
+			name: "Thresholds equal to supported values",
+			check: model.UptimeCheck{
+				ResponseTimeThreshold: 5 * time.Second,
+				AlertAfterFailures:    2,
+				RecoveryPeriod:        3 * time.Minute,
+			},
+			wantRequestTimeout:     intPtr(5),
+			wantConfirmationPeriod: intPtr(60),
+			wantRecoveryPeriod:     intPtr(180),
+			wantWarnings:           1, // the response time threshold is always applied as request timeout
+		},
+		{
+			name:                   "Alert on first failure",
+			check:                  model.UptimeCheck{AlertAfterFailures: 1},
+			wantConfirmationPeriod: intPtr(0),
+		},
+		{
+			name:  "No request timeout for ping checks",
+			check: model.UptimeCheck{Type: model.CheckTypePing, ResponseTimeThreshold: 4 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := MonitorCreateOrUpdateRequest{CheckFrequency: 60}
+			warnings := setThresholds(&request, tt.check)
+			assert.Equal(t, tt.wantRequestTimeout, request.RequestTimeout)
+			assert.Equal(t, tt.wantConfirmationPeriod, request.ConfirmationPeriod)
+			assert.Equal(t, tt.wantRecoveryPeriod, request.RecoveryPeriod)
+			assert.Len(t, warnings, tt.wantWarnings)
+		})
+	}
+}
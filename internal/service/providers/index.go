@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ErrNotFound returned when an uptime check (or related resource) doesn't exist (anymore) at the uptime provider
+var ErrNotFound = errors.New("not found at uptime provider")
+
+// IDIndex in-memory index of uptime check IDs (from the annotations) to the IDs of the checks at the
+// uptime provider. Populated by listing all checks once, on first use, and kept up to date on creates
+// and deletes. This avoids listing all checks at the uptime provider on every reconcile.
+type IDIndex struct {
+	list func(ctx context.Context) (map[string]int64, error)
+
+	lock sync.Mutex
+	ids  map[string]int64 // nil when not (yet) populated
+}
+
+// NewIDIndex creates an IDIndex, the given list func should return all checks at the uptime provider
+func NewIDIndex(list func(ctx context.Context) (map[string]int64, error)) *IDIndex {
+	return &IDIndex{list: list}
+}
+
+// Get returns the ID at the uptime provider of the given check ID, or CheckNotFound when there's no such check
+func (i *IDIndex) Get(ctx context.Context, checkID string) (int64, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.populate(ctx); err != nil {
+		return CheckNotFound, err
+	}
+	providerID, ok := i.ids[checkID]
+	if !ok {
+		return CheckNotFound, nil
+	}
+	return providerID, nil
+}
+
+// CheckIDs returns all check IDs in the index
+func (i *IDIndex) CheckIDs(ctx context.Context) ([]string, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.populate(ctx); err != nil {
+		return nil, err
+	}
+	return slices.Collect(maps.Keys(i.ids)), nil
+}
+
+// Set records the ID at the uptime provider of the given (newly created) check ID
+func (i *IDIndex) Set(checkID string, providerID int64) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if i.ids != nil {
+		i.ids[checkID] = providerID
+	}
+}
+
+// Delete removes the given check ID from the index
+func (i *IDIndex) Delete(checkID string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	delete(i.ids, checkID)
+}
+
+// Invalidate clears the index, so all checks are listed again on next use. To be called when
+// the index turns out to be out-of-sync with the uptime provider (e.g. when checks are
+// modified outside the operator).
+func (i *IDIndex) Invalidate() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.ids = nil
+}
+
+// populate lists all checks at the uptime provider, when not done already. Caller should hold the lock.
+func (i *IDIndex) populate(ctx context.Context) error {
+	if i.ids != nil {
+		return nil
+	}
+	ids, err := i.list(ctx)
+	if err != nil {
+		return err
+	}
+	if ids == nil {
+		ids = make(map[string]int64)
+	}
+	i.ids = ids
+	return nil
+}
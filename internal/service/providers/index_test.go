@@ -0,0 +1,58 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDIndex(t *testing.T) {
+	listings := 0
+	index := NewIDIndex(func(_ context.Context) (map[string]int64, error) {
+		listings++
+		return map[string]int64{"a": 1}, nil
+	})
+
+	id, err := index.Get(context.TODO(), "a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+
+	id, err = index.Get(context.TODO(), "b")
+	assert.NoError(t, err)
+	assert.Equal(t, CheckNotFound, id)
+
+	index.Set("b", 2)
+	id, _ = index.Get(context.TODO(), "b")
+	assert.Equal(t, int64(2), id)
+
+	index.Delete("a")
+	id, _ = index.Get(context.TODO(), "a")
+	assert.Equal(t, CheckNotFound, id)
+	assert.Equal(t, 1, listings, "checks should only be listed once")
+
+	index.Invalidate()
+	id, _ = index.Get(context.TODO(), "a")
+	assert.Equal(t, int64(1), id)
+	assert.Equal(t, 2, listings)
+}
+
+func TestIDIndex_ListError(t *testing.T) {
+	fail := true
+	index := NewIDIndex(func(_ context.Context) (map[string]int64, error) {
+		if fail {
+			return nil, errors.New("failed")
+		}
+		return map[string]int64{"a": 1}, nil
+	})
+
+	id, err := index.Get(context.TODO(), "a")
+	assert.Error(t, err)
+	assert.Equal(t, CheckNotFound, id)
+
+	fail = false
+	id, err = index.Get(context.TODO(), "a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+}
@@ -0,0 +1,15 @@
+package providers
+
+import "time"
+
+// NearestInterval returns the supported interval nearest to the given interval. When the given
+// interval lies exactly between two supported intervals the smallest one is used.
+func NearestInterval(interval time.Duration, supportedIntervals []time.Duration) time.Duration {
+	nearest := supportedIntervals[0]
+	for _, supported := range supportedIntervals[1:] {
+		if (supported - interval).Abs() < (nearest - interval).Abs() {
+			nearest = supported
+		}
+	}
+	return nearest
+}
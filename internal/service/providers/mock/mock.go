@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
 
 	"github.com/PDOK/uptime-operator/internal/model"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -11,6 +14,7 @@ import (
 
 type Mock struct {
 	checks map[string]model.UptimeCheck
+	lock   sync.Mutex
 }
 
 func New() *Mock {
@@ -20,7 +24,9 @@ func New() *Mock {
 }
 
 func (m *Mock) CreateOrUpdateCheck(ctx context.Context, check model.UptimeCheck) error {
+	m.lock.Lock()
 	m.checks[check.ID] = check
+	m.lock.Unlock()
 
 	checkJSON, _ := json.Marshal(check)
 	log.FromContext(ctx).Info(fmt.Sprintf("MOCK: created or updated check %s\n", checkJSON))
@@ -29,10 +35,57 @@ func (m *Mock) CreateOrUpdateCheck(ctx context.Context, check model.UptimeCheck)
 }
 
 func (m *Mock) DeleteCheck(ctx context.Context, check model.UptimeCheck) error {
+	m.lock.Lock()
 	delete(m.checks, check.ID)
+	m.lock.Unlock()
 
 	checkJSON, _ := json.Marshal(check)
 	log.FromContext(ctx).Info(fmt.Sprintf("MOCK: deleted check %s\n", checkJSON))
 
 	return nil
 }
+
+func (m *Mock) ListCheckIDs(_ context.Context, tag string) ([]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if tag == "" {
+		return slices.Collect(maps.Keys(m.checks)), nil
+	}
+	var result []string
+	for id, check := range m.checks {
+		if slices.Contains(check.Tags, tag) {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+func (m *Mock) CheckExists(_ context.Context, check model.UptimeCheck) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, exists := m.checks[check.ID]
+	return exists, nil
+}
+
+func (m *Mock) Payload(check model.UptimeCheck, _ bool) ([]byte, error) {
+	return json.Marshal(check)
+}
+
+func (m *Mock) CurrentPayload(_ context.Context, check model.UptimeCheck) ([]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return json.Marshal(m.checks[check.ID])
+}
+
+func (m *Mock) ListUnmanagedChecks(_ context.Context) ([]model.UnmanagedCheck, error) {
+	return nil, nil
+}
+
+func (m *Mock) AdoptCheck(ctx context.Context, providerID string, check model.UptimeCheck) error {
+	log.FromContext(ctx).Info(fmt.Sprintf("MOCK: adopted check %s as %s\n", providerID, check.ID))
+	return nil
+}
+
+func (m *Mock) FindUnmanagedCheck(_ context.Context, _ model.UptimeCheck, _ model.AdoptionPolicy) (string, error) {
+	return "", nil
+}
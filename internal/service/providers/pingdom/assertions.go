@@ -0,0 +1,9 @@
+package pingdom
+
+import "github.com/PDOK/uptime-operator/internal/model"
+
+// UnsupportedResponseAssertions Pingdom HTTP checks only assert whether the response (doesn't) contain a
+// string, none of the other response assertions are supported.
+func (p *Pingdom) UnsupportedResponseAssertions(check model.UptimeCheck) []string {
+	return check.ResponseAssertionAnnotations()
+}
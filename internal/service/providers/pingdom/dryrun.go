@@ -0,0 +1,94 @@
+package pingdom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+	"github.com/PDOK/uptime-operator/internal/service/providers"
+)
+
+// CheckExists whether the given check exists with Pingdom
+func (p *Pingdom) CheckExists(ctx context.Context, check model.UptimeCheck) (bool, error) {
+	pingdomCheckID, err := p.findCheck(ctx, check)
+	return pingdomCheckID != providers.CheckNotFound, err
+}
+
+// Payload returns the JSON message to create (or otherwise update) the given check with Pingdom
+func (p *Pingdom) Payload(check model.UptimeCheck, create bool) ([]byte, error) {
+	return p.checkToJSON(check, create)
+}
+
+// CurrentPayload returns the current state of the given check with Pingdom, in the form of the
+// message to update the check (see checkToJSON). Fields which are write-only aren't returned.
+func (p *Pingdom) CurrentPayload(ctx context.Context, check model.UptimeCheck) ([]byte, error) {
+	pingdomCheckID, err := p.findCheck(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	if pingdomCheckID == providers.CheckNotFound {
+		return nil, fmt.Errorf("check %s: %w", check.ID, providers.ErrNotFound)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%d?include_teams=false", pingdomURL, pingdomCheckID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(providers.HeaderAccept, providers.MediaTypeJSON)
+	var detailsResponse struct {
+		Check map[string]any `json:"check"`
+	}
+	if err = p.execRequestWithBodyAndResponse(ctx, req, &detailsResponse); err != nil {
+		return nil, err
+	}
+	return json.Marshal(detailsToMessage(detailsResponse.Check))
+}
+
+// detailsToMessage converts the details of a Pingdom check to the form of the message to update a check
+func detailsToMessage(details map[string]any) map[string]any {
+	message := make(map[string]any)
+	for _, field := range []string{"name", "resolution", "responsetime_threshold", "sendnotificationwhendown",
+		"probe_filters", "userids", "integrationids"} {
+		if value, ok := details[field]; ok {
+			message[field] = value
+		}
+	}
+	message["host"] = details["hostname"]
+	message["paused"] = details["status"] == "paused"
+	var tags []string
+	if rawTags, ok := details["tags"].([]any); ok {
+		for _, rawTag := range rawTags {
+			if tag, ok := rawTag.(map[string]any); ok {
+				tags = append(tags, fmt.Sprint(tag["name"]))
+			}
+		}
+	}
+	message["tags"] = tags
+
+	// type specific fields are nested, e.g. {"type": {"http": {"url": "/path", ...}}}
+	types, _ := details["type"].(map[string]any)
+	for _, rawTypeDetails := range types {
+		typeDetails, _ := rawTypeDetails.(map[string]any)
+		for _, field := range []string{"url", "encryption", "port", "shouldcontain", "shouldnotcontain", "postdata",
+			"verify_certificate", "ssl_down_days_before", "stringtosend", "stringtoexpect", "expectedip", "nameserver"} {
+			message[field] = typeDetails[field]
+		}
+		// request headers are returned as object, while these are submitted as numbered fields
+		requestHeaders, _ := typeDetails["requestheaders"].(map[string]any)
+		var headers []string
+		for header, value := range requestHeaders {
+			if header == providers.HeaderUserAgent && strings.HasPrefix(fmt.Sprint(value), defaultUserAgentPrefix) {
+				continue
+			}
+			headers = append(headers, header)
+		}
+		sort.Strings(headers)
+		for i, header := range headers {
+			message[fmt.Sprintf("requestheader%d", i)] = fmt.Sprintf("%s:%v", header, requestHeaders[header])
+		}
+	}
+	return message
+}
@@ -0,0 +1,43 @@
+package pingdom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetailsToMessage(t *testing.T) {
+	var details map[string]any
+	err := json.Unmarshal([]byte(`{
+		"id": 85975,
+		"name": "My check",
+		"hostname": "pdok.example",
+		"resolution": 1,
+		"status": "paused",
+		"tags": [{"name": "managed-by-uptime-operator", "type": "u", "count": 1}],
+		"type": {
+			"http": {
+				"url": "/path",
+				"encryption": true,
+				"port": 443,
+				"shouldcontain": "OK",
+				"requestheaders": {"User-Agent": "Pingdom.com_bot_version_1.4", "Accept": "application/json"}
+			}
+		}
+	}`), &details)
+	assert.NoError(t, err)
+
+	message := detailsToMessage(details)
+	assert.Equal(t, "My check", message["name"])
+	assert.Equal(t, "pdok.example", message["host"])
+	assert.InDelta(t, 1, message["resolution"], 0)
+	assert.Equal(t, true, message["paused"])
+	assert.Equal(t, []string{"managed-by-uptime-operator"}, message["tags"])
+	assert.Equal(t, "/path", message["url"])
+	assert.Equal(t, "OK", message["shouldcontain"])
+	assert.Nil(t, message["shouldnotcontain"])
+	assert.Equal(t, "Accept:application/json", message["requestheader0"])
+	assert.NotContains(t, message, "requestheader1", "default User-Agent of Pingdom shouldn't be compared")
+	assert.NotContains(t, message, "id")
+}
@@ -0,0 +1,259 @@
+package pingdom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+	"github.com/PDOK/uptime-operator/internal/service/providers"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultUserAgentPrefix Pingdom adds its own User-Agent header to all HTTP checks
+const defaultUserAgentPrefix = "Pingdom.com_bot"
+
+type checkTag struct {
+	Name string `json:"name"`
+}
+
+// checkDetails details of a Pingdom check, as far as these can be expressed in annotations
+type checkDetails struct {
+	ID                       int64                  `json:"id"`
+	Name                     string                 `json:"name"`
+	Hostname                 string                 `json:"hostname"`
+	Status                   string                 `json:"status"`
+	Resolution               int                    `json:"resolution"`
+	Tags                     []checkTag             `json:"tags"`
+	ProbeFilters             []string               `json:"probe_filters"`
+	ResponseTimeThreshold    int                    `json:"responsetime_threshold"`
+	SendNotificationWhenDown int                    `json:"sendnotificationwhendown"`
+	Type                     map[string]typeDetails `json:"type"`
+}
+
+// typeDetails details of a Pingdom check specific to the type of check
+type typeDetails struct {
+	URL               string            `json:"url"`
+	Encryption        bool              `json:"encryption"`
+	Port              int               `json:"port"`
+	ShouldContain     string            `json:"shouldcontain"`
+	ShouldNotContain  string            `json:"shouldnotcontain"`
+	PostData          string            `json:"postdata"`
+	RequestHeaders    map[string]string `json:"requestheaders"`
+	VerifyCertificate bool              `json:"verify_certificate"`
+	SSLDownDaysBefore int               `json:"ssl_down_days_before"`
+	StringToSend      string            `json:"stringtosend"`
+	StringToExpect    string            `json:"stringtoexpect"`
+	ExpectedIP        string            `json:"expectedip"`
+	Nameserver        string            `json:"nameserver"`
+}
+
+// checkSummary check as listed by Pingdom, without most settings
+type checkSummary struct {
+	ID       int64      `json:"id"`
+	Name     string     `json:"name"`
+	Hostname string     `json:"hostname"`
+	Tags     []checkTag `json:"tags"`
+}
+
+// ListUnmanagedChecks returns all checks at Pingdom which aren't managed by the operator. Since the
+// list of checks lacks most settings, the details of each unmanaged check are retrieved separately.
+func (p *Pingdom) ListUnmanagedChecks(ctx context.Context) ([]model.UnmanagedCheck, error) {
+	summaries, err := p.listAllChecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []model.UnmanagedCheck
+	for _, summary := range summaries {
+		if isManaged(summary.Tags) {
+			continue
+		}
+		details, err := p.getCheckDetails(ctx, summary.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, detailsToCheck(details))
+	}
+	log.FromContext(ctx).Info("listed unmanaged checks", "count", len(result))
+	return result, nil
+}
+
+// FindUnmanagedCheck returns the Pingdom ID of the single unmanaged check which matches the given check according
+// to the given policy. Only the details of unmanaged checks with the same hostname (and name) are retrieved.
+func (p *Pingdom) FindUnmanagedCheck(ctx context.Context, check model.UptimeCheck, policy model.AdoptionPolicy) (string, error) {
+	pingdomCheckID, err := p.findCheck(ctx, check)
+	if err != nil || pingdomCheckID != providers.CheckNotFound {
+		return "", err
+	}
+	checkURL, err := url.Parse(check.URL)
+	if err != nil {
+		return "", err
+	}
+	summaries, err := p.listAllChecks(ctx)
+	if err != nil {
+		return "", err
+	}
+	var matches []model.UnmanagedCheck
+	for _, summary := range summaries {
+		if isManaged(summary.Tags) || !strings.EqualFold(summary.Hostname, checkURL.Hostname()) ||
+			(policy == model.AdoptionPolicyNameAndURL && summary.Name != check.Name) {
+			continue
+		}
+		details, err := p.getCheckDetails(ctx, summary.ID)
+		if err != nil {
+			return "", err
+		}
+		if candidate := detailsToCheck(details); policy.Matches(check, candidate.UptimeCheck) {
+			matches = append(matches, candidate)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0].ProviderID, nil
+	default:
+		log.FromContext(ctx).Info("multiple unmanaged checks match, not adopting any of these",
+			"check", check.ID, "matches", len(matches))
+		return "", nil
+	}
+}
+
+// listAllChecks returns all checks at Pingdom, both managed and unmanaged
+func (p *Pingdom) listAllChecks(ctx context.Context) ([]checkSummary, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pingdomURL+"?include_tags=true&limit=25000", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(providers.HeaderAccept, providers.MediaTypeJSON)
+	var checksResponse struct {
+		Checks []checkSummary `json:"checks"`
+	}
+	err = p.execRequestWithBodyAndResponse(ctx, req, &checksResponse)
+	return checksResponse.Checks, err
+}
+
+// AdoptCheck takes ownership of the unmanaged Pingdom check with the given ID, by adding the
+// tag of the operator and the tag with the ID of the given check
+func (p *Pingdom) AdoptCheck(ctx context.Context, providerID string, check model.UptimeCheck) error {
+	pingdomCheckID, err := strconv.ParseInt(providerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Pingdom check ID '%s': %w", providerID, err)
+	}
+	log.FromContext(ctx).Info("adopting check", "check", check.ID, "pingdom ID", pingdomCheckID)
+
+	message, err := json.Marshal(map[string]any{
+		"addtags": []string{model.TagManagedBy, idTag(check.ID)},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/%d", pingdomURL, pingdomCheckID), bytes.NewBuffer(message))
+	if err != nil {
+		return err
+	}
+	if err = p.execRequestWithBody(ctx, req); err != nil {
+		return err
+	}
+	p.index.Set(idTag(check.ID), pingdomCheckID)
+	return nil
+}
+
+func (p *Pingdom) getCheckDetails(ctx context.Context, pingdomCheckID int64) (checkDetails, error) {
+	var detailsResponse struct {
+		Check checkDetails `json:"check"`
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%d?include_teams=false", pingdomURL, pingdomCheckID), nil)
+	if err != nil {
+		return detailsResponse.Check, err
+	}
+	req.Header.Add(providers.HeaderAccept, providers.MediaTypeJSON)
+	err = p.execRequestWithBodyAndResponse(ctx, req, &detailsResponse)
+	return detailsResponse.Check, err
+}
+
+// isManaged whether the given tags of a Pingdom check indicate the check is managed by the operator
+func isManaged(tags []checkTag) bool {
+	return slices.ContainsFunc(tags, func(tag checkTag) bool {
+		return tag.Name == model.TagManagedBy || strings.HasPrefix(tag.Name, customIDPrefix)
+	})
+}
+
+// detailsToCheck converts the details of a Pingdom check to an (unmanaged) uptime check, the inverse of checkToJSON
+func detailsToCheck(details checkDetails) model.UnmanagedCheck {
+	check := model.UptimeCheck{
+		Name:                  details.Name,
+		Interval:              time.Duration(details.Resolution) * time.Minute,
+		Paused:                details.Status == "paused",
+		ResponseTimeThreshold: time.Duration(details.ResponseTimeThreshold) * time.Millisecond,
+		AlertAfterFailures:    details.SendNotificationWhenDown,
+	}
+	for _, tag := range details.Tags {
+		check.Tags = append(check.Tags, tag.Name)
+	}
+	for _, filter := range details.ProbeFilters {
+		if region, ok := strings.CutPrefix(filter, "region: "); ok {
+			check.Regions = append(check.Regions, strings.ToLower(region))
+		}
+	}
+	for pingdomType, typeDetails := range details.Type {
+		switch pingdomType {
+		case "http":
+			addHTTPDetails(&check, details.Hostname, typeDetails)
+		case "tcp":
+			check.Type = model.CheckTypeTCP
+			check.URL = fmt.Sprintf("tcp://%s:%d", details.Hostname, typeDetails.Port)
+			check.TCPStringToSend = typeDetails.StringToSend
+			check.TCPStringToExpect = typeDetails.StringToExpect
+		case "ping":
+			check.Type = model.CheckTypePing
+			check.URL = "ping://" + details.Hostname
+		case "dns":
+			check.Type = model.CheckTypeDNS
+			check.URL = "dns://" + details.Hostname
+			check.DNSExpectedIP = typeDetails.ExpectedIP
+			check.DNSNameserver = typeDetails.Nameserver
+		}
+	}
+	return model.UnmanagedCheck{
+		ProviderID:  strconv.FormatInt(details.ID, 10),
+		UptimeCheck: check,
+	}
+}
+
+func addHTTPDetails(check *model.UptimeCheck, hostname string, details typeDetails) {
+	check.Type = model.CheckTypeHTTP
+	checkURL := &url.URL{Scheme: "http", Host: hostname}
+	if details.Encryption {
+		checkURL.Scheme = "https"
+	}
+	if defaultPort, _ := model.GetPort(checkURL); details.Port > 0 && details.Port != defaultPort {
+		checkURL.Host += ":" + strconv.Itoa(details.Port)
+	}
+	check.URL = checkURL.String() + details.URL
+	check.StringContains = details.ShouldContain
+	check.StringNotContains = details.ShouldNotContain
+	if details.PostData != "" {
+		check.HTTPMethod = http.MethodPost
+		check.RequestBody = model.Secret(details.PostData)
+	}
+	for header, value := range details.RequestHeaders {
+		if header == providers.HeaderUserAgent && strings.HasPrefix(value, defaultUserAgentPrefix) {
+			continue
+		}
+		if check.RequestHeaders == nil {
+			check.RequestHeaders = make(map[string]string)
+		}
+		check.RequestHeaders[header] = value
+	}
+	if details.VerifyCertificate && details.SSLDownDaysBefore > 0 && details.Encryption {
+		check.TLSExpiryAlertDays = details.SSLDownDaysBefore
+	}
+}
@@ -0,0 +1,95 @@
+package pingdom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetailsToCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		details string
+		want    model.UnmanagedCheck
+	}{
+		{
+			name: "HTTP check",
+			details: `{
+				"id": 85975,
+				"name": "My check",
+				"hostname": "pdok.example",
+				"resolution": 5,
+				"status": "up",
+				"tags": [{"name": "team-a", "type": "u", "count": 1}],
+				"probe_filters": ["region: EU"],
+				"responsetime_threshold": 1500,
+				"type": {
+					"http": {
+						"url": "/path?service=WMS",
+						"encryption": true,
+						"port": 8443,
+						"shouldcontain": "OK",
+						"verify_certificate": true,
+						"ssl_down_days_before": 14,
+						"requestheaders": {"User-Agent": "Pingdom.com_bot_version_1.4", "Accept": "application/json"}
+					}
+				}
+			}`,
+			want: model.UnmanagedCheck{
+				ProviderID: "85975",
+				UptimeCheck: model.UptimeCheck{
+					Name:                  "My check",
+					Type:                  model.CheckTypeHTTP,
+					URL:                   "https://pdok.example:8443/path?service=WMS",
+					Tags:                  []string{"team-a"},
+					Interval:              5 * time.Minute,
+					RequestHeaders:        map[string]string{"Accept": "application/json"},
+					StringContains:        "OK",
+					TLSExpiryAlertDays:    14,
+					Regions:               []string{"eu"},
+					ResponseTimeThreshold: 1500 * time.Millisecond,
+				},
+			},
+		},
+		{
+			name: "Paused TCP check",
+			details: `{
+				"id": 123,
+				"name": "Database",
+				"hostname": "db.example",
+				"resolution": 1,
+				"status": "paused",
+				"type": {"tcp": {"port": 5432, "stringtoexpect": "ready"}}
+			}`,
+			want: model.UnmanagedCheck{
+				ProviderID: "123",
+				UptimeCheck: model.UptimeCheck{
+					Name:              "Database",
+					Type:              model.CheckTypeTCP,
+					URL:               "tcp://db.example:5432",
+					Interval:          time.Minute,
+					TCPStringToExpect: "ready",
+					Paused:            true,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var details checkDetails
+			require.NoError(t, json.Unmarshal([]byte(tt.details), &details))
+			assert.Equal(t, tt.want, detailsToCheck(details))
+		})
+	}
+}
+
+func TestIsManaged(t *testing.T) {
+	assert.False(t, isManaged(nil))
+	assert.False(t, isManaged([]checkTag{{Name: "team-a"}}))
+	assert.True(t, isManaged([]checkTag{{Name: model.TagManagedBy}}))
+	assert.True(t, isManaged([]checkTag{{Name: "id:abc"}}))
+}
@@ -0,0 +1,21 @@
+package pingdom
+
+import (
+	"time"
+
+	"github.com/PDOK/uptime-operator/internal/service/providers"
+)
+
+// supportedIntervals Pingdom only accepts a specific set of intervals ("resolution")
+var supportedIntervals = []time.Duration{
+	time.Minute, 5 * time.Minute, 15 * time.Minute, 30 * time.Minute, 60 * time.Minute,
+}
+
+func (p *Pingdom) SupportedIntervals() []time.Duration {
+	return supportedIntervals
+}
+
+// toResolution returns the nearest supported interval in minutes
+func toResolution(interval time.Duration) int {
+	return int(providers.NearestInterval(interval, supportedIntervals).Minutes())
+}
@@ -0,0 +1,158 @@
+package pingdom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+	"github.com/PDOK/uptime-operator/internal/service/providers"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	pingdomMaintenanceURL = "https://api.pingdom.com/api/3.1/maintenance"
+	maintenancePageSize   = 100
+)
+
+type maintenanceWindow struct {
+	ID          int64  `json:"id"`
+	Description string `json:"description"`
+	From        int64  `json:"from"`
+	To          int64  `json:"to"`
+}
+
+// syncMaintenanceWindow registers the current/next occurrence of the maintenance window of the given
+// check as a one-off Pingdom maintenance window. Since only one occurrence is registered at a time
+// the check should be revisited once the occurrence has ended, in order to register the next one.
+// Only call for checks with a maintenance window, see DeleteMaintenanceWindows to remove windows.
+func (p *Pingdom) syncMaintenanceWindow(ctx context.Context, pingdomCheckID int64, check model.UptimeCheck) error {
+	existingWindows, err := p.findMaintenanceWindows(ctx, check)
+	if err != nil {
+		return err
+	}
+	from, to, ok := check.Maintenance.Occurrence(time.Now())
+	if !ok {
+		// no active or upcoming occurrence, remove leftovers
+		return p.deleteMaintenanceWindowsByID(ctx, existingWindows)
+	}
+	if len(existingWindows) == 0 {
+		log.FromContext(ctx).Info("creating maintenance window", "check", check.ID, "from", from, "to", to)
+		return p.putMaintenanceWindow(ctx, http.MethodPost, pingdomMaintenanceURL, pingdomCheckID, check, from, to)
+	}
+	// update the first window, delete any others
+	existing := existingWindows[0]
+	if existing.From != from.Unix() || existing.To != to.Unix() {
+		log.FromContext(ctx).Info("updating maintenance window", "check", check.ID, "from", from, "to", to)
+		err = p.putMaintenanceWindow(ctx, http.MethodPut, fmt.Sprintf("%s/%d", pingdomMaintenanceURL, existing.ID),
+			pingdomCheckID, check, from, to)
+		if err != nil {
+			return err
+		}
+	}
+	return p.deleteMaintenanceWindowsByID(ctx, existingWindows[1:])
+}
+
+// DeleteMaintenanceWindows removes the Pingdom maintenance window(s) of the given check
+func (p *Pingdom) DeleteMaintenanceWindows(ctx context.Context, check model.UptimeCheck) error {
+	existingWindows, err := p.findMaintenanceWindows(ctx, check)
+	if err != nil {
+		return err
+	}
+	return p.deleteMaintenanceWindowsByID(ctx, existingWindows)
+}
+
+// findMaintenanceWindows returns the Pingdom maintenance windows of the given check, walks all pages of the listing
+func (p *Pingdom) findMaintenanceWindows(ctx context.Context, check model.UptimeCheck) ([]maintenanceWindow, error) {
+	var result []maintenanceWindow
+	for offset := 0; ; offset += maintenancePageSize {
+		windows, err := p.listMaintenanceWindows(ctx, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, window := range windows {
+			if window.Description == maintenanceDescription(check) {
+				result = append(result, window)
+			}
+		}
+		if len(windows) < maintenancePageSize {
+			return result, nil
+		}
+	}
+}
+
+// listMaintenanceWindows returns one page of Pingdom maintenance windows, starting at the given offset
+func (p *Pingdom) listMaintenanceWindows(ctx context.Context, offset int) ([]maintenanceWindow, error) {
+	url := fmt.Sprintf("%s?limit=%d&offset=%d", pingdomMaintenanceURL, maintenancePageSize, offset)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(providers.HeaderAccept, providers.MediaTypeJSON)
+	resp, err := p.execRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status %d, expected HTTP OK when listing maintenance windows", resp.StatusCode)
+	}
+
+	var maintenanceResponse struct {
+		Maintenance []maintenanceWindow `json:"maintenance"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&maintenanceResponse); err != nil {
+		return nil, err
+	}
+	return maintenanceResponse.Maintenance, nil
+}
+
+func (p *Pingdom) putMaintenanceWindow(ctx context.Context, method string, url string, pingdomCheckID int64,
+	check model.UptimeCheck, from time.Time, to time.Time) error {
+	message, err := json.Marshal(map[string]any{
+		"description":    maintenanceDescription(check),
+		"from":           from.Unix(),
+		"to":             to.Unix(),
+		"recurrencetype": "none",
+		"uptimeids":      strconv.FormatInt(pingdomCheckID, 10),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(message))
+	if err != nil {
+		return err
+	}
+	return p.execRequestWithBody(ctx, req)
+}
+
+func (p *Pingdom) deleteMaintenanceWindowsByID(ctx context.Context, windows []maintenanceWindow) error {
+	for _, window := range windows {
+		log.FromContext(ctx).Info("deleting maintenance window", "description", window.Description, "pingdom ID", window.ID)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", pingdomMaintenanceURL, window.ID), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := p.execRequest(ctx, req)
+		if err != nil {
+			return err
+		}
+		resultBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("got status %d, expected HTTP OK when deleting maintenance window. Error %s", resp.StatusCode, resultBody)
+		}
+	}
+	return nil
+}
+
+// maintenanceDescription the description is used to link a Pingdom maintenance window to a check
+func maintenanceDescription(check model.UptimeCheck) string {
+	return fmt.Sprintf("%s %s%s", model.OperatorName, customIDPrefix, check.ID)
+}
@@ -8,10 +8,11 @@ import (
 	"fmt"
 	"io"
 	classiclog "log"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -22,19 +23,19 @@ import (
 
 const pingdomURL = "https://api.pingdom.com/api/3.1/checks"
 const customIDPrefix = "id:"
-
-const headerReqLimitShort = "Req-Limit-Short"
-const headerReqLimitLong = "Req-Limit-Long"
+const maxTagLength = 64
 
 type Settings struct {
 	APIToken       string
 	UserIDs        []int
 	IntegrationIDs []int
+	Timeout        time.Duration // of a single API call, including retries
 }
 
 type Pingdom struct {
 	settings   Settings
 	httpClient *http.Client
+	index      *providers.IDIndex
 }
 
 // New creates a Pingdom
@@ -42,24 +43,52 @@ func New(settings Settings) *Pingdom {
 	if settings.APIToken == "" {
 		classiclog.Fatal("Pingdom API token is not provided")
 	}
-	return &Pingdom{
-		settings:   settings,
-		httpClient: &http.Client{Timeout: time.Duration(5) * time.Minute},
+	if settings.Timeout <= 0 {
+		settings.Timeout = providers.DefaultTimeout
+	}
+	p := &Pingdom{
+		settings: settings,
+		httpClient: &http.Client{
+			Timeout:   settings.Timeout,
+			Transport: providers.NewTransport(rateLimitWait),
+		},
 	}
+	p.index = providers.NewIDIndex(p.listChecks)
+	return p
 }
 
 // CreateOrUpdateCheck create the given check with Pingdom, or update an existing check. Needs to be idempotent!
 func (p *Pingdom) CreateOrUpdateCheck(ctx context.Context, check model.UptimeCheck) (err error) {
-	existingCheckID, err := p.findCheck(ctx, check)
+	pingdomCheckID, err := p.findCheck(ctx, check)
 	if err != nil {
 		return err
 	}
-	if existingCheckID == providers.CheckNotFound {
-		err = p.createCheck(ctx, check)
-	} else {
-		err = p.updateCheck(ctx, existingCheckID, check)
+	if pingdomCheckID != providers.CheckNotFound {
+		err = p.updateCheck(ctx, pingdomCheckID, check)
+		if errors.Is(err, providers.ErrNotFound) {
+			// check was deleted outside the operator, the index is out-of-sync
+			p.index.Invalidate()
+			if pingdomCheckID, err = p.findCheck(ctx, check); err != nil {
+				return err
+			}
+			if pingdomCheckID != providers.CheckNotFound {
+				err = p.updateCheck(ctx, pingdomCheckID, check)
+			}
+		}
+	}
+	if err == nil && pingdomCheckID == providers.CheckNotFound {
+		pingdomCheckID, err = p.createCheck(ctx, check)
+	}
+	if err != nil || check.Maintenance == nil {
+		return err
 	}
-	return err
+	return p.syncMaintenanceWindow(ctx, pingdomCheckID, check)
+}
+
+// SupportsMaintenanceWindow Pingdom supports all maintenance windows, since we register
+// the current/next occurrence of a window as a one-off maintenance window with Pingdom.
+func (p *Pingdom) SupportsMaintenanceWindow(_ model.MaintenanceWindow) bool {
+	return true
 }
 
 // DeleteCheck deletes the given check from Pingdom
@@ -75,7 +104,7 @@ func (p *Pingdom) DeleteCheck(ctx context.Context, check model.UptimeCheck) erro
 		return nil
 	}
 
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", pingdomURL, existingCheckID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", pingdomURL, existingCheckID), nil)
 	if err != nil {
 		return err
 	}
@@ -84,79 +113,151 @@ func (p *Pingdom) DeleteCheck(ctx context.Context, check model.UptimeCheck) erro
 		return err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
 		resultBody, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("got status %d, expected HTTP OK when deleting existing check. Error %s", resp.StatusCode, resultBody)
 	}
-	return nil
+	p.index.Delete(idTag(check.ID))
+	return p.DeleteMaintenanceWindows(ctx, check)
 }
 
+// findCheck returns the Pingdom ID of the given check, or CheckNotFound when there's no such check. Checks
+// missing from the index are looked up by their ID tag, since these may have been adopted outside this
+// process (e.g. by the import command), in order to update such a check instead of creating a duplicate.
 func (p *Pingdom) findCheck(ctx context.Context, check model.UptimeCheck) (int64, error) {
-	result := providers.CheckNotFound
+	tag := idTag(check.ID)
+	pingdomCheckID, err := p.index.Get(ctx, tag)
+	if err != nil || pingdomCheckID != providers.CheckNotFound {
+		return pingdomCheckID, err
+	}
+	checks, err := p.listChecksWithTag(ctx, tag)
+	if err != nil {
+		return providers.CheckNotFound, err
+	}
+	pingdomCheckID, ok := checks[tag]
+	if !ok {
+		return providers.CheckNotFound, nil
+	}
+	p.index.Set(tag, pingdomCheckID)
+	return pingdomCheckID, nil
+}
+
+// ListCheckIDs returns the IDs of all checks at Pingdom managed by the operator (with the given tag). Since
+// Pingdom tags are limited in length, IDs which are possibly cut off aren't returned.
+func (p *Pingdom) ListCheckIDs(ctx context.Context, tag string) ([]string, error) {
+	var tags []string
+	var err error
+	if tag == "" {
+		tags, err = p.index.CheckIDs(ctx)
+	} else {
+		var checks map[string]int64
+		checks, err = p.listChecksWithTag(ctx, tag)
+		tags = slices.Collect(maps.Keys(checks))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, tag := range tags {
+		if len(tag) < maxTagLength {
+			result = append(result, strings.TrimPrefix(tag, customIDPrefix))
+		}
+	}
+	return result, nil
+}
+
+// listChecks returns the Pingdom IDs of all checks managed by uptime-operator, by ID tag
+func (p *Pingdom) listChecks(ctx context.Context) (map[string]int64, error) {
+	return p.listChecksWithTag(ctx, model.TagManagedBy)
+}
 
-	// list all checks managed by uptime-operator. Can be at most 25.000, which is probably sufficient.
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s?include_tags=true&limit=25000&tags=%s", pingdomURL, model.TagManagedBy), nil)
+// listChecksWithTag returns the Pingdom IDs of the checks managed by uptime-operator with the given tag, by ID tag
+func (p *Pingdom) listChecksWithTag(ctx context.Context, tag string) (map[string]int64, error) {
+	// list all checks with the tag. Can be at most 25.000, which is probably sufficient.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s?include_tags=true&limit=25000&tags=%s", pingdomURL, url.QueryEscape(tag)), nil)
 	if err != nil {
-		return result, err
+		return nil, err
 	}
 	req.Header.Add(providers.HeaderAccept, providers.MediaTypeJSON)
 	resp, err := p.execRequest(ctx, req)
 	if err != nil {
-		return result, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return result, fmt.Errorf("got status %d, expected HTTP OK when listing existing checks", resp.StatusCode)
+		return nil, fmt.Errorf("got status %d, expected HTTP OK when listing existing checks", resp.StatusCode)
 	}
 
-	checksResponse := make(map[string]any)
+	var checksResponse struct {
+		Checks []struct {
+			ID   int64      `json:"id"`
+			Tags []checkTag `json:"tags"`
+		} `json:"checks"`
+	}
 	err = json.NewDecoder(resp.Body).Decode(&checksResponse)
 	if err != nil {
-		return result, err
+		return nil, err
 	}
 
-	pingdomChecks := checksResponse["checks"].([]any)
-	for _, rawCheck := range pingdomChecks {
-		pingdomCheck := rawCheck.(map[string]any)
-		tags := pingdomCheck["tags"]
-		if tags == nil {
+	// the check ID (from the annotation) is stored in a Pingdom tag, index
+	// these tags by the actual Pingdom ID which we need for updates/deletes/etc.
+	result := make(map[string]int64)
+	for _, pingdomCheck := range checksResponse.Checks {
+		if !slices.Contains(pingdomCheck.Tags, checkTag{Name: model.TagManagedBy}) {
 			continue
 		}
-		for _, rawTag := range tags.([]any) {
-			tag := rawTag.(map[string]any)
-			if tag == nil {
-				continue
-			}
-			tagName := tag["name"].(string)
-			if strings.HasSuffix(tagName, check.ID) {
-				// bingo, we've found the Pingdom check based on our custom ID (check.ID which is stored in a Pingdom tag).
-				// now we return the actual Pingdom ID which we need for updates/deletes/etc.
-				pingdomCheckID := pingdomCheck["id"]
-				if pingdomCheckID != nil && pingdomCheckID.(float64) > 0 {
-					result = int64(pingdomCheckID.(float64))
-				}
+		for _, tag := range pingdomCheck.Tags {
+			if strings.HasPrefix(tag.Name, customIDPrefix) && pingdomCheck.ID > 0 {
+				result[tag.Name] = pingdomCheck.ID
 			}
 		}
 	}
+	log.FromContext(ctx).Info("listed existing checks", "count", len(result))
 	return result, nil
 }
 
-func (p *Pingdom) createCheck(ctx context.Context, check model.UptimeCheck) error {
+// MaxCheckIDLength Pingdom can't combine shouldcontain and shouldnotcontain in one check, so checks asserting both
+// are split. The ID of a check is stored in a tag of at most 64 chars, tags of that length are possibly truncated.
+func (p *Pingdom) MaxCheckIDLength() int {
+	return maxTagLength - len(customIDPrefix) - 1
+}
+
+// idTag returns the Pingdom tag containing the given check ID
+func idTag(checkID string) string {
+	return truncateTag(customIDPrefix + checkID)
+}
+
+// truncateTag tags can be at most 64 chars long, cut off longer ones
+func truncateTag(tag string) string {
+	if len(tag) > maxTagLength {
+		return tag[:maxTagLength]
+	}
+	return tag
+}
+
+func (p *Pingdom) createCheck(ctx context.Context, check model.UptimeCheck) (int64, error) {
 	log.FromContext(ctx).Info("creating check", "check", check)
 
 	message, err := p.checkToJSON(check, true)
 	if err != nil {
-		return err
+		return providers.CheckNotFound, err
 	}
-	req, err := http.NewRequest(http.MethodPost, pingdomURL, bytes.NewBuffer(message))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pingdomURL, bytes.NewBuffer(message))
 	if err != nil {
-		return err
+		return providers.CheckNotFound, err
 	}
-	err = p.execRequestWithBody(ctx, req)
+	var createResponse struct {
+		Check struct {
+			ID int64 `json:"id"`
+		} `json:"check"`
+	}
+	err = p.execRequestWithBodyAndResponse(ctx, req, &createResponse)
 	if err != nil {
-		return err
+		return providers.CheckNotFound, err
 	}
-	return nil
+	p.index.Set(idTag(check.ID), createResponse.Check.ID)
+	return createResponse.Check.ID, nil
 }
 
 func (p *Pingdom) updateCheck(ctx context.Context, existingPingdomID int64, check model.UptimeCheck) error {
@@ -166,7 +267,7 @@ func (p *Pingdom) updateCheck(ctx context.Context, existingPingdomID int64, chec
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/%d", pingdomURL, existingPingdomID), bytes.NewBuffer(message))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/%d", pingdomURL, existingPingdomID), bytes.NewBuffer(message))
 	if err != nil {
 		return err
 	}
@@ -182,40 +283,51 @@ func (p *Pingdom) checkToJSON(check model.UptimeCheck, includeType bool) ([]byte
 	if err != nil {
 		return nil, err
 	}
-	port, err := getPort(checkURL)
-	if err != nil {
-		return nil, err
-	}
-	relativeURL := checkURL.Path
-	if checkURL.RawQuery != "" {
-		relativeURL += "?" + checkURL.RawQuery
-	}
 
 	// add the check id (from the k8s annotation) as a tag, so
 	// we can latter retrieve the check during update or delete.
-	check.Tags = append(check.Tags, customIDPrefix+check.ID)
-
-	// tags can be at most 64 chars long, cut off longer ones
-	for k := range check.Tags {
-		tag := check.Tags[k]
-		if len(tag) > 64 {
-			tag = tag[:64]
-		}
-		check.Tags[k] = tag
+	tags := make([]string, 0, len(check.Tags)+1)
+	for _, tag := range check.Tags {
+		tags = append(tags, truncateTag(tag))
 	}
+	tags = append(tags, idTag(check.ID))
 
 	message := map[string]any{
 		"name":       check.Name,
 		"host":       checkURL.Hostname(),
-		"url":        relativeURL,
-		"encryption": true, // assume all checks run over HTTPS
-		"port":       port,
-		"resolution": check.Interval,
-		"tags":       check.Tags,
+		"resolution": toResolution(check.Interval),
+		"tags":       tags,
+		"paused":     check.Paused,
+	}
+	pingdomType := "http"
+	switch check.Type {
+	case model.CheckTypeTCP:
+		pingdomType = "tcp"
+		err = addTCPFields(message, checkURL, check)
+	case model.CheckTypePing:
+		pingdomType = "ping"
+	case model.CheckTypeDNS:
+		pingdomType = "dns"
+		message["expectedip"] = check.DNSExpectedIP
+		message["nameserver"] = check.DNSNameserver
+	default:
+		err = addHTTPFields(message, checkURL, check)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if message["probe_filters"], err = toProbeFilters(check.Regions); err != nil {
+		return nil, err
+	}
+	if check.ResponseTimeThreshold > 0 {
+		message["responsetime_threshold"] = check.ResponseTimeThreshold.Milliseconds()
+	}
+	if check.AlertAfterFailures > 0 {
+		message["sendnotificationwhendown"] = check.AlertAfterFailures
 	}
 	if includeType {
 		// update messages shouldn't include 'type', since the type of check can't be modified in Pingdom.
-		message["type"] = "http"
+		message["type"] = pingdomType
 	}
 	if len(p.settings.UserIDs) > 0 {
 		message["userids"] = p.settings.UserIDs
@@ -223,16 +335,52 @@ func (p *Pingdom) checkToJSON(check model.UptimeCheck, includeType bool) ([]byte
 	if len(p.settings.IntegrationIDs) > 0 {
 		message["integrationids"] = p.settings.IntegrationIDs
 	}
+	return json.Marshal(message)
+}
+
+func addHTTPFields(message map[string]any, checkURL *url.URL, check model.UptimeCheck) error {
+	port, err := model.GetPort(checkURL)
+	if err != nil {
+		return err
+	}
+	relativeURL := checkURL.Path
+	if checkURL.RawQuery != "" {
+		relativeURL += "?" + checkURL.RawQuery
+	}
+	message["url"] = relativeURL
+	message["encryption"] = providers.IsHTTPS(checkURL)
+	message["port"] = port
+
+	// Pingdom sends a GET request, or a POST request when "postdata" is provided
+	switch check.HTTPMethod {
+	case "", http.MethodGet:
+	case http.MethodPost:
+		if check.RequestBody == "" {
+			return errors.New("POST requests without a request body aren't supported by Pingdom")
+		}
+		message["postdata"] = check.RequestBody.Reveal()
+	default:
+		return fmt.Errorf("HTTP method %s isn't supported by Pingdom, only GET and POST", check.HTTPMethod)
+	}
+	if check.BasicAuth != nil {
+		message["auth"] = check.BasicAuth.Username + ":" + check.BasicAuth.Password.Reveal()
+	}
+
+	if check.TLSExpiryAlertDays > 0 {
+		message["verify_certificate"] = true
+		message["ssl_down_days_before"] = check.TLSExpiryAlertDays
+	}
 
 	// request header need to be submitted in numbered JSON keys
 	// for example "requestheader1": key:value, "requestheader2": key:value, etc
+	requestHeaders := check.RevealRequestHeaders()
 	var headers []string
-	for header := range check.RequestHeaders {
+	for header := range requestHeaders {
 		headers = append(headers, header)
 	}
 	sort.Strings(headers)
 	for i, header := range headers {
-		message[fmt.Sprintf("requestheader%d", i)] = fmt.Sprintf("%s:%s", header, check.RequestHeaders[header])
+		message[fmt.Sprintf("requestheader%d", i)] = fmt.Sprintf("%s:%s", header, requestHeaders[header])
 	}
 
 	// Pingdom doesn't allow both "shouldcontain" and "shouldnotcontain"
@@ -241,62 +389,52 @@ func (p *Pingdom) checkToJSON(check model.UptimeCheck, includeType bool) ([]byte
 	} else if check.StringNotContains != "" {
 		message["shouldnotcontain"] = check.StringNotContains
 	}
+	return nil
+}
 
-	return json.Marshal(message)
+func addTCPFields(message map[string]any, checkURL *url.URL, check model.UptimeCheck) error {
+	port, err := model.GetPort(checkURL)
+	if err != nil {
+		return err
+	}
+	message["port"] = port
+	if check.TCPStringToSend != "" {
+		message["stringtosend"] = check.TCPStringToSend
+	}
+	if check.TCPStringToExpect != "" {
+		message["stringtoexpect"] = check.TCPStringToExpect
+	}
+	return nil
 }
 
 func (p *Pingdom) execRequestWithBody(ctx context.Context, req *http.Request) error {
+	return p.execRequestWithBodyAndResponse(ctx, req, nil)
+}
+
+// execRequestWithBodyAndResponse same as execRequestWithBody but decodes the
+// JSON response body into the given target (when not nil).
+func (p *Pingdom) execRequestWithBodyAndResponse(ctx context.Context, req *http.Request, target any) error {
 	req.Header.Add(providers.HeaderContentType, providers.MediaTypeJSON)
 	resp, err := p.execRequest(ctx, req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("got http status %d: %w", resp.StatusCode, providers.ErrNotFound)
+	}
 	if resp.StatusCode != http.StatusOK {
 		resultBody, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("got http status %d, while expected 200. Error: %s", resp.StatusCode, resultBody)
 	}
-	return nil
+	if target == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
 }
 
 func (p *Pingdom) execRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
 	req.Header.Add(providers.HeaderAuthorization, "Bearer "+p.settings.APIToken)
 	req.Header.Add(providers.HeaderUserAgent, model.OperatorName)
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return resp, err
-	}
-	rateLimitErr := errors.Join(
-		handleRateLimits(ctx, resp.Header.Get(headerReqLimitShort)),
-		handleRateLimits(ctx, resp.Header.Get(headerReqLimitLong)),
-	)
-	return resp, rateLimitErr
-}
-
-func handleRateLimits(ctx context.Context, rateLimitHeader string) error {
-	remaining, resetTime, err := parseRateLimitHeader(rateLimitHeader)
-	if err != nil {
-		return err
-	}
-	if remaining < 25 {
-		log.FromContext(ctx).Info(
-			fmt.Sprintf("Waiting for %d seconds to avoid hitting Pingdom rate limit", resetTime+1),
-			rateLimitHeader, remaining)
-
-		time.Sleep(time.Duration(remaining+1) * time.Second)
-	}
-	return nil
-}
-
-func parseRateLimitHeader(header string) (remaining int, resetTime int, err error) {
-	_, err = fmt.Sscanf(header, "Remaining: %d Time until reset: %d", &remaining, &resetTime)
-	return
-}
-
-func getPort(checkURL *url.URL) (int, error) {
-	port := checkURL.Port()
-	if port == "" {
-		port = "443"
-	}
-	return strconv.Atoi(port)
+	return p.httpClient.Do(req)
 }
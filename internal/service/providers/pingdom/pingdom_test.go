@@ -2,8 +2,12 @@ package pingdom
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -90,7 +94,7 @@ func TestAgainstREALPingdomAPI(t *testing.T) {
 
 			// create/update/delete actual check with REAL pingdom API.
 			m := New(settings)
-			check, err := model.NewUptimeCheck("foo", tt.annotations)
+			check, err := model.NewUptimeCheck(context.TODO(), "foo", tt.annotations, nil)
 			assert.NoError(t, err)
 			if tt.wantDelete {
 				if err := m.DeleteCheck(context.TODO(), *check); (err != nil) != tt.wantErr {
@@ -112,3 +116,191 @@ func TestAgainstREALPingdomAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckToJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		check          model.UptimeCheck
+		wantEncryption bool
+		wantPort       float64
+		wantURL        string
+		wantType       string
+		wantErr        bool
+	}{
+		{
+			name:           "HTTPS with default port",
+			check:          model.UptimeCheck{ID: "1", URL: "https://pdok.example/path?foo=bar"},
+			wantEncryption: true,
+			wantPort:       443,
+			wantURL:        "/path?foo=bar",
+			wantType:       "http",
+		},
+		{
+			name:           "HTTP with default port",
+			check:          model.UptimeCheck{ID: "1", URL: "http://pdok.example/path"},
+			wantEncryption: false,
+			wantPort:       80,
+			wantURL:        "/path",
+		},
+		{
+			name:           "HTTP with custom port",
+			check:          model.UptimeCheck{ID: "1", URL: "http://pdok.example:8080/path"},
+			wantEncryption: false,
+			wantPort:       8080,
+			wantURL:        "/path",
+		},
+		{
+			name:     "TCP check",
+			check:    model.UptimeCheck{ID: "1", Type: model.CheckTypeTCP, URL: "tcp://pdok.example:5432"},
+			wantType: "tcp",
+			wantPort: 5432,
+		},
+		{
+			name:     "Ping check",
+			check:    model.UptimeCheck{ID: "1", Type: model.CheckTypePing, URL: "ping://pdok.example"},
+			wantType: "ping",
+		},
+		{
+			name:     "DNS check",
+			check:    model.UptimeCheck{ID: "1", Type: model.CheckTypeDNS, URL: "dns://pdok.example", DNSExpectedIP: "192.0.2.1", DNSNameserver: "ns1.example"},
+			wantType: "dns",
+		},
+		{
+			name:    "Unknown scheme without port",
+			check:   model.UptimeCheck{ID: "1", URL: "foo://pdok.example/path"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pingdom{}
+			result, err := p.checkToJSON(tt.check, true)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var message map[string]any
+			assert.NoError(t, json.Unmarshal(result, &message))
+			assert.Equal(t, "pdok.example", message["host"])
+			if tt.wantType != "" {
+				assert.Equal(t, tt.wantType, message["type"])
+			}
+			if tt.wantType == "ping" || tt.wantType == "dns" {
+				assert.NotContains(t, message, "port")
+				return
+			}
+			assert.InDelta(t, tt.wantPort, message["port"], 0)
+			if tt.wantType == "tcp" {
+				assert.NotContains(t, message, "url")
+				return
+			}
+			assert.Equal(t, tt.wantEncryption, message["encryption"])
+			assert.Equal(t, tt.wantURL, message["url"])
+		})
+	}
+}
+
+func TestCheckToJSON_HTTPRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		check        model.UptimeCheck
+		wantPostData any
+		wantAuth     any
+		wantErr      bool
+	}{
+		{
+			name:  "GET",
+			check: model.UptimeCheck{ID: "1", URL: "https://pdok.example", HTTPMethod: "GET"},
+		},
+		{
+			name:         "POST with body and basic auth",
+			check:        model.UptimeCheck{ID: "1", URL: "https://pdok.example", HTTPMethod: "POST", RequestBody: "request=GetRecords", BasicAuth: &model.BasicAuth{Username: "user", Password: "s3cr3t"}},
+			wantPostData: "request=GetRecords",
+			wantAuth:     "user:s3cr3t",
+		},
+		{
+			name:    "POST without body",
+			check:   model.UptimeCheck{ID: "1", URL: "https://pdok.example", HTTPMethod: "POST"},
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported method",
+			check:   model.UptimeCheck{ID: "1", URL: "https://pdok.example", HTTPMethod: "PUT", RequestBody: "foo"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pingdom{}
+			result, err := p.checkToJSON(tt.check, true)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var message map[string]any
+			assert.NoError(t, json.Unmarshal(result, &message))
+			assert.Equal(t, tt.wantPostData, message["postdata"])
+			assert.Equal(t, tt.wantAuth, message["auth"])
+		})
+	}
+}
+
+func TestCheckToJSON_Thresholds(t *testing.T) {
+	p := &Pingdom{}
+	check := model.UptimeCheck{ID: "1", URL: "https://pdok.example", ResponseTimeThreshold: 1500 * time.Millisecond, AlertAfterFailures: 3}
+	result, err := p.checkToJSON(check, true)
+	assert.NoError(t, err)
+	var message map[string]any
+	assert.NoError(t, json.Unmarshal(result, &message))
+	assert.InDelta(t, 1500, message["responsetime_threshold"], 0)
+	assert.InDelta(t, 3, message["sendnotificationwhendown"], 0)
+
+	result, err = p.checkToJSON(model.UptimeCheck{ID: "1", URL: "https://pdok.example"}, true)
+	assert.NoError(t, err)
+	message = nil
+	assert.NoError(t, json.Unmarshal(result, &message))
+	assert.NotContains(t, message, "responsetime_threshold")
+	assert.NotContains(t, message, "sendnotificationwhendown")
+
+	assert.Empty(t, p.ApproximatedThresholds(check))
+	check.RecoveryPeriod = 5 * time.Minute
+	assert.Len(t, p.ApproximatedThresholds(check), 1, "recovery period isn't supported")
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCreateOrUpdateCheck_AdoptedOutsideProcess(t *testing.T) {
+	adopted := false
+	var requests []string
+	p := New(Settings{APIToken: "token"})
+	p.httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		body := `{}`
+		if req.Method == http.MethodGet && adopted {
+			body = `{"checks": [{"id": 123, "tags": [{"name": "managed-by-uptime-operator"}, {"name": "id:adopted"}]}]}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+	})
+
+	// populate the index before the check is adopted, e.g. by the import command
+	exists, err := p.CheckExists(context.TODO(), model.UptimeCheck{ID: "other"})
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	adopted = true
+
+	requests = nil
+	err = p.CreateOrUpdateCheck(context.TODO(), model.UptimeCheck{ID: "adopted", URL: "https://pdok.example"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET /api/3.1/checks", "PUT /api/3.1/checks/123"}, requests, "adopted check should be updated")
+
+	requests = nil
+	err = p.CreateOrUpdateCheck(context.TODO(), model.UptimeCheck{ID: "adopted", URL: "https://pdok.example"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PUT /api/3.1/checks/123"}, requests, "adopted check should be indexed")
+}
@@ -0,0 +1,34 @@
+package pingdom
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const headerReqLimitShort = "Req-Limit-Short"
+const headerReqLimitLong = "Req-Limit-Long"
+
+// minRemainingRequests hold off requests when fewer requests remain before hitting the Pingdom rate limit
+const minRemainingRequests = 25
+
+// rateLimitWait returns how long to wait to avoid hitting the Pingdom rate limits, based on the
+// Req-Limit-Short and Req-Limit-Long response headers. See https://docs.pingdom.com/api/#section/Rate-limiting
+func rateLimitWait(resp *http.Response) time.Duration {
+	var result time.Duration
+	for _, header := range []string{headerReqLimitShort, headerReqLimitLong} {
+		remaining, resetTime, err := parseRateLimitHeader(resp.Header.Get(header))
+		if err != nil {
+			continue // header absent or unparsable, nothing to go on
+		}
+		if remaining < minRemainingRequests {
+			result = max(result, time.Duration(resetTime+1)*time.Second)
+		}
+	}
+	return result
+}
+
+func parseRateLimitHeader(header string) (remaining int, resetTime int, err error) {
+	_, err = fmt.Sscanf(header, "Remaining: %d Time until reset: %d", &remaining, &resetTime)
+	return
+}
@@ -0,0 +1,44 @@
+package pingdom
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name:   "No headers",
+			header: http.Header{},
+			want:   0,
+		},
+		{
+			name: "Sufficient requests remaining",
+			header: http.Header{
+				headerReqLimitShort: {"Remaining: 394 Time until reset: 3589"},
+				headerReqLimitLong:  {"Remaining: 71994 Time until reset: 2591989"},
+			},
+			want: 0,
+		},
+		{
+			name: "Short limit almost reached",
+			header: http.Header{
+				headerReqLimitShort: {"Remaining: 10 Time until reset: 30"},
+				headerReqLimitLong:  {"Remaining: 71994 Time until reset: 2591989"},
+			},
+			want: 31 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rateLimitWait(&http.Response{Header: tt.header}))
+		})
+	}
+}
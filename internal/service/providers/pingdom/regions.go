@@ -0,0 +1,41 @@
+package pingdom
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// regions Pingdom probe regions, by (lowercase) region name or alias
+var regions = map[string]string{
+	"eu":    "EU",
+	"na":    "NA",
+	"us":    "NA",
+	"apac":  "APAC",
+	"as":    "APAC",
+	"latam": "LATAM",
+}
+
+// SupportedRegions returns the region names and aliases supported by Pingdom
+func (p *Pingdom) SupportedRegions() []string {
+	return slices.Sorted(maps.Keys(regions))
+}
+
+// toProbeFilters converts region names to Pingdom probe filters (like "region: EU"). Returns
+// an empty list when no regions are given, which removes existing filters from a check.
+func toProbeFilters(checkRegions []string) ([]string, error) {
+	result := []string{}
+	for _, region := range checkRegions {
+		pingdomRegion, ok := regions[strings.ToLower(region)]
+		if !ok {
+			return nil, fmt.Errorf("region '%s' isn't supported by Pingdom, should be one of %v",
+				region, slices.Sorted(maps.Keys(regions)))
+		}
+		filter := "region: " + pingdomRegion
+		if !slices.Contains(result, filter) {
+			result = append(result, filter)
+		}
+	}
+	return result, nil
+}
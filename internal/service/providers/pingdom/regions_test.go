@@ -0,0 +1,43 @@
+package pingdom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToProbeFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		regions []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "No regions",
+			regions: nil,
+			want:    []string{},
+		},
+		{
+			name:    "Regions with aliases",
+			regions: []string{"eu", "us", "na"},
+			want:    []string{"region: EU", "region: NA"},
+		},
+		{
+			name:    "Unsupported region",
+			regions: []string{"au"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toProbeFilters(tt.regions)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package pingdom
+
+import (
+	"fmt"
+
+	"github.com/PDOK/uptime-operator/internal/model"
+)
+
+// ApproximatedThresholds Pingdom applies the response time threshold and the number of failures
+// as is, but doesn't support a recovery period.
+func (p *Pingdom) ApproximatedThresholds(check model.UptimeCheck) []string {
+	if check.RecoveryPeriod == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s of uptime check '%s' (id: %s) isn't supported by the uptime provider and is ignored.",
+		model.AnnotationRecoveryPeriod, check.Name, check.ID)}
+}
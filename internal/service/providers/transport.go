@@ -0,0 +1,176 @@
+package providers
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultTimeout default max duration of a single call to the uptime provider API, including retries
+const DefaultTimeout = 5 * time.Minute
+
+const (
+	defaultMaxRetries = 5
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = time.Minute
+)
+
+// RateLimitFunc returns how long to hold off further requests based on the (provider-specific)
+// rate limit headers of the given response. Returns zero when there's no need to wait.
+type RateLimitFunc func(resp *http.Response) time.Duration
+
+// Transport http.RoundTripper shared by the uptime providers which handles rate limiting and transient
+// errors. Requests are retried with jittered exponential backoff on HTTP 429 (Too Many Requests) and,
+// for idempotent requests, on server errors and connection errors. A Retry-After header or the
+// provider-specific rate limit headers (see RateLimitFunc) hold off all requests using this transport.
+// Waiting is aborted when the context of the request is canceled.
+type Transport struct {
+	Base       http.RoundTripper
+	RateLimit  RateLimitFunc
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	lock      sync.Mutex
+	notBefore time.Time // no requests should be sent before this moment
+}
+
+// NewTransport creates a Transport with default settings, the given RateLimitFunc is optional
+func NewTransport(rateLimit RateLimitFunc) *Transport {
+	return &Transport{
+		Base:       http.DefaultTransport,
+		RateLimit:  rateLimit,
+		MaxRetries: defaultMaxRetries,
+		MinBackoff: defaultMinBackoff,
+		MaxBackoff: defaultMaxBackoff,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := t.awaitNotBefore(req); err != nil {
+			return nil, err
+		}
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.Base.RoundTrip(attemptReq)
+		if err == nil && t.RateLimit != nil {
+			t.holdOff(t.RateLimit(resp))
+		}
+		if attempt >= t.MaxRetries || !shouldRetry(req, resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		wait := t.backoff(attempt)
+		if resp != nil {
+			wait = max(wait, retryAfter(resp))
+			drainAndClose(resp)
+		}
+		log.FromContext(ctx).Info("retrying request to uptime provider", "method", req.Method,
+			"url", req.URL.Redacted(), "attempt", attempt+1, "wait", wait.String(), "reason", retryReason(resp, err))
+		t.holdOff(wait)
+	}
+}
+
+// holdOff prevents any requests from being sent for the given duration
+func (t *Transport) holdOff(wait time.Duration) {
+	if wait <= 0 {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if notBefore := time.Now().Add(wait); notBefore.After(t.notBefore) {
+		t.notBefore = notBefore
+	}
+}
+
+func (t *Transport) awaitNotBefore(req *http.Request) error {
+	t.lock.Lock()
+	wait := time.Until(t.notBefore)
+	t.lock.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// backoff returns the jittered exponential backoff for the given attempt
+func (t *Transport) backoff(attempt int) time.Duration {
+	backoff := t.MinBackoff << attempt
+	if backoff <= 0 || backoff > t.MaxBackoff {
+		backoff = t.MaxBackoff
+	}
+	// between 50% and 100% of the backoff
+	return backoff/2 + rand.N(backoff/2+1) //nolint:gosec // no need for a secure random here
+}
+
+// rewind returns the request to send for the given attempt, with a fresh body for retries
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	result := req.Clone(req.Context())
+	result.Body = body
+	return result, nil
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true // request wasn't processed, so safe to retry regardless of the method
+	}
+	idempotent := slices.Contains([]string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}, req.Method)
+	if err != nil {
+		return idempotent
+	}
+	return idempotent && resp.StatusCode >= http.StatusInternalServerError
+}
+
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// retryAfter parses the Retry-After header, either in seconds or as HTTP date
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func drainAndClose(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTransport(rateLimit RateLimitFunc) *Transport {
+	transport := NewTransport(rateLimit)
+	transport.MinBackoff = time.Millisecond
+	transport.MaxBackoff = 10 * time.Millisecond
+	transport.MaxRetries = 3
+	return transport
+}
+
+// newTestServer responds with the given status codes in order, and with HTTP OK afterward
+func newTestServer(statusCodes []int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		body, _ := io.ReadAll(r.Body)
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		if n <= len(statusCodes) {
+			w.WriteHeader(statusCodes[n-1])
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	return server, &requests
+}
+
+func TestTransport_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		statusCodes  []int
+		wantStatus   int
+		wantRequests int32
+	}{
+		{
+			name:         "Success",
+			method:       http.MethodGet,
+			wantStatus:   http.StatusOK,
+			wantRequests: 1,
+		},
+		{
+			name:         "Too many requests",
+			method:       http.MethodPost,
+			statusCodes:  []int{http.StatusTooManyRequests, http.StatusTooManyRequests},
+			wantStatus:   http.StatusOK,
+			wantRequests: 3,
+		},
+		{
+			name:         "Server error on idempotent request",
+			method:       http.MethodPut,
+			statusCodes:  []int{http.StatusBadGateway},
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+		{
+			name:         "Server error on non-idempotent request",
+			method:       http.MethodPost,
+			statusCodes:  []int{http.StatusBadGateway},
+			wantStatus:   http.StatusBadGateway,
+			wantRequests: 1,
+		},
+		{
+			name:         "Client error",
+			method:       http.MethodGet,
+			statusCodes:  []int{http.StatusBadRequest},
+			wantStatus:   http.StatusBadRequest,
+			wantRequests: 1,
+		},
+		{
+			name:   "Max retries",
+			method: http.MethodGet,
+			statusCodes: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+				http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantRequests: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newTestServer(tt.statusCodes, nil)
+			defer server.Close()
+			client := &http.Client{Transport: newTestTransport(nil)}
+
+			req, err := http.NewRequest(tt.method, server.URL, bytes.NewBufferString("body"))
+			assert.NoError(t, err)
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantRequests, requests.Load())
+			if resp.StatusCode == http.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, "body", string(body), "body should be resent on retries")
+			}
+		})
+	}
+}
+
+func TestTransport_RetryAfter(t *testing.T) {
+	server, requests := newTestServer([]int{http.StatusTooManyRequests}, http.Header{"Retry-After": {"1"}})
+	defer server.Close()
+	client := &http.Client{Transport: newTestTransport(nil)}
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestTransport_RateLimit(t *testing.T) {
+	server, _ := newTestServer(nil, nil)
+	defer server.Close()
+	transport := newTestTransport(func(_ *http.Response) time.Duration { return time.Hour })
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	// next request should wait for the rate limit, until the context is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err = client.Do(req) //nolint:bodyclose // no response expected
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,8 @@
+package providers
+
+import "net/url"
+
+// IsHTTPS whether the given URL uses TLS
+func IsHTTPS(checkURL *url.URL) bool {
+	return checkURL.Scheme == "https"
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+)
+
+// ValidateDefaultRegions returns an error when the provider doesn't support one of the default regions
+func (r *UptimeCheckService) ValidateDefaultRegions() error {
+	supporter, ok := r.provider.(RegionSupporter)
+	if !ok {
+		return nil
+	}
+	supported := supporter.SupportedRegions()
+	for _, region := range r.defaults.Regions {
+		if !slices.Contains(supported, strings.ToLower(region)) {
+			return fmt.Errorf("default region '%s' isn't supported by the uptime provider, should be one of %v",
+				region, supported)
+		}
+	}
+	return nil
+}
+
+// filterRegions removes the regions of the check which the provider doesn't support, returns a
+// warning for each removed region. Without any regions left the provider defaults are used.
+func (r *UptimeCheckService) filterRegions(check *m.UptimeCheck) (warnings []string) {
+	supporter, ok := r.provider.(RegionSupporter)
+	if !ok {
+		return nil
+	}
+	supported := supporter.SupportedRegions()
+	var regions []string
+	for _, region := range check.Regions {
+		if slices.Contains(supported, strings.ToLower(region)) {
+			regions = append(regions, region)
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("uptime check '%s' (id: %s) uses region '%s', which the uptime "+
+			"provider doesn't support (should be one of %v), ignoring this region", check.Name, check.ID, region, supported))
+	}
+	check.Regions = regions
+	return warnings
+}
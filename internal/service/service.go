@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	classiclog "log"
+	"slices"
+	"time"
 
 	m "github.com/PDOK/uptime-operator/internal/model"
 	p "github.com/PDOK/uptime-operator/internal/service/providers"
@@ -19,6 +22,23 @@ type UptimeCheckService struct {
 	provider      UptimeProvider
 	slack         *Slack
 	enableDeletes bool
+	defaults      m.CheckDefaults
+	dryRun        bool
+
+	// whether (and how) to adopt existing unmanaged checks instead of creating new checks
+	adoptionPolicy m.AdoptionPolicy
+
+	// limits the number of concurrent calls to the uptime provider, nil means unlimited
+	providerSemaphore chan struct{}
+
+	// key of the hashes of applied checks, see m.UptimeCheck.Hash
+	hashKey []byte
+
+	// tag added to all checks of this operator instance, scopes the deletion of orphaned checks
+	ownerTag string
+
+	// whether to delete orphaned checks, see DeleteOrphanedChecks
+	deleteOrphans bool
 }
 
 func New(options ...UptimeCheckOption) *UptimeCheckService {
@@ -68,27 +88,321 @@ func WithDeletes(enableDeletes bool) UptimeCheckOption {
 	}
 }
 
-func (r *UptimeCheckService) Mutate(ctx context.Context, mutation m.Mutation, ingressName string, annotations map[string]string) {
+// WithDefaults operator-wide defaults for checks, applied to settings which aren't specified on the ingress route
+func WithDefaults(defaults m.CheckDefaults) UptimeCheckOption {
+	return func(service *UptimeCheckService) *UptimeCheckService {
+		service.defaults = defaults
+		return service
+	}
+}
+
+// WithDryRun only logs (and reports on Slack) the mutations of uptime checks, instead of executing them
+func WithDryRun(dryRun bool) UptimeCheckOption {
+	return func(service *UptimeCheckService) *UptimeCheckService {
+		service.dryRun = dryRun
+		return service
+	}
+}
+
+// WithHashKey signs the hashes of applied checks (stored in the last-applied annotation) with the given key,
+// so these don't give away the secret values of the checks. Changing the key results in updating all checks once.
+func WithHashKey(key string) UptimeCheckOption {
+	return func(service *UptimeCheckService) *UptimeCheckService {
+		service.hashKey = []byte(key)
+		return service
+	}
+}
+
+// WithOwnerTag adds the given tag to all checks, to tell the checks of this operator instance apart from checks
+// of other instances (e.g. in other clusters) using the same uptime provider account.
+func WithOwnerTag(tag string) UptimeCheckOption {
+	return func(service *UptimeCheckService) *UptimeCheckService {
+		service.ownerTag = tag
+		return service
+	}
+}
+
+// WithOrphanDeletes deletes checks with the owner tag which don't belong to any ingress route, see DeleteOrphanedChecks
+func WithOrphanDeletes(deleteOrphans bool) UptimeCheckOption {
+	return func(service *UptimeCheckService) *UptimeCheckService {
+		service.deleteOrphans = deleteOrphans
+		return service
+	}
+}
+
+// WithProviderConcurrency limits the number of concurrent calls to the uptime provider (zero means unlimited),
+// so ingress routes can be reconciled concurrently while staying within the limits of the uptime provider.
+func WithProviderConcurrency(concurrency int) UptimeCheckOption {
+	return func(service *UptimeCheckService) *UptimeCheckService {
+		if concurrency > 0 {
+			service.providerSemaphore = make(chan struct{}, concurrency)
+		}
+		return service
+	}
+}
+
+// WithAdoptionPolicy adopts an existing check which isn't managed by the operator (yet) when a new check would be
+// created, provided the check matches according to the given policy. Only when supported by the provider.
+func WithAdoptionPolicy(policy m.AdoptionPolicy) UptimeCheckOption {
+	return func(service *UptimeCheckService) *UptimeCheckService {
+		service.adoptionPolicy = policy
+		return service
+	}
+}
+
+// MutationResult outcome of a mutation, for the caller to act upon
+type MutationResult struct {
+	// RequeueAfter when non-zero the ingress route should be mutated again after
+	// this duration, for example to start or end a maintenance window.
+	RequeueAfter time.Duration
+
+	// LastApplied when not nil should be stored on the ingress route (as annotation),
+	// so checks which are no longer part of the route can be removed on the next mutation.
+	LastApplied *m.LastApplied
+
+	// Warnings about uptime checks which couldn't be applied exactly as specified, to be reported on the ingress route.
+	Warnings []string
+}
+
+// Mutate creates/updates or deletes the uptime check(s) of the given ingress route. The resolver
+// is used to resolve references to Secrets and ConfigMaps in the annotations of the ingress route.
+func (r *UptimeCheckService) Mutate(ctx context.Context, mutation m.Mutation, ingressName string,
+	annotations map[string]string, resolver m.Resolver) (result MutationResult) {
 	_, ignore := annotations[m.AnnotationIgnore]
 	if ignore {
 		r.logRouteIgnore(ctx, mutation, ingressName)
 		return
 	}
-	check, err := m.NewUptimeCheck(ingressName, annotations)
-	if err != nil {
+	if mutation == m.Delete {
+		resolver = nil // deletes only need the ID, and shouldn't fail on missing Secrets or ConfigMaps
+	}
+	checks, err := m.NewUptimeChecks(ctx, ingressName, annotations, resolver)
+	var prepareErr error
+	checks, result.Warnings, prepareErr = r.prepareChecks(mutation, checks)
+	if err = errors.Join(err, prepareErr); err != nil {
 		r.logAnnotationErr(ctx, err)
-		return
 	}
-	if mutation == m.CreateOrUpdate {
-		err = r.provider.CreateOrUpdateCheck(ctx, *check)
-		r.logMutation(ctx, err, mutation, check)
-	} else if mutation == m.Delete {
-		if !r.enableDeletes {
-			r.logDeleteDisabled(ctx, check)
-			return
+	r.logWarnings(ctx, result.Warnings)
+	lastApplied, lastAppliedErr := m.GetLastApplied(annotations)
+	if lastAppliedErr != nil {
+		r.logAnnotationErr(ctx, lastAppliedErr)
+	}
+	now := time.Now()
+
+	// Remove checks that were applied before but are no longer part of the route (e.g. because the ID
+	// has changed), before creating new ones. Only when all annotations are valid, to avoid removing
+	// checks because of a typo. Stale checks are kept in last-applied until they're actually deleted,
+	// so these are retried on the next mutation instead of being orphaned.
+	applied := m.NewLastApplied(checks)
+	if err == nil && lastAppliedErr == nil {
+		for _, staleID := range lastApplied.StaleIDs(checks) {
+			staleCheck := m.UptimeCheck{ID: staleID, Name: "previous check of " + ingressName}
+			if !r.deleteCheck(ctx, &staleCheck) {
+				applied = applied.Merge(m.LastApplied{IDs: []string{staleID}})
+			}
 		}
-		err = r.provider.DeleteCheck(ctx, *check)
-		r.logMutation(ctx, err, mutation, check)
+	} else {
+		applied = applied.Merge(lastApplied)
+	}
+
+	for i := range checks {
+		requeueAfter, hash, _ := r.mutateCheck(ctx, mutation, &checks[i], lastApplied, now)
+		if requeueAfter > 0 && (result.RequeueAfter == 0 || requeueAfter < result.RequeueAfter) {
+			result.RequeueAfter = requeueAfter
+		}
+		if hash != "" {
+			applied.SetApplied(checks[i], hash)
+		}
+	}
+	if mutation == m.CreateOrUpdate && !r.dryRun {
+		result.LastApplied = &applied
+	}
+	return
+}
+
+// prepareChecks applies the operator-wide defaults to the given checks, and adapts them to the capabilities
+// of the uptime provider. Returns warnings when checks can't be applied exactly as specified, and an error
+// for checks which can't be applied at all. The latter are left out of the result.
+func (r *UptimeCheckService) prepareChecks(mutation m.Mutation, checks []m.UptimeCheck) ([]m.UptimeCheck, []string, error) {
+	for i := range checks {
+		checks[i].ApplyDefaults(r.defaults)
+		if r.ownerTag != "" && !slices.Contains(checks[i].Tags, r.ownerTag) {
+			checks[i].Tags = append(checks[i].Tags, r.ownerTag)
+		}
+	}
+	checks, warnings := r.splitStringAssertions(checks)
+	if mutation != m.CreateOrUpdate {
+		return checks, warnings, nil
+	}
+	var result []m.UptimeCheck
+	var errs []error
+	for i := range checks {
+		if err := r.checkResponseAssertions(checks[i]); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		warnings = append(warnings, r.filterRegions(&checks[i])...)
+		warnings = append(warnings, r.roundInterval(&checks[i])...)
+		warnings = append(warnings, r.approximateThresholds(checks[i])...)
+		result = append(result, checks[i])
+	}
+	return result, warnings, errors.Join(errs...)
+}
+
+// mutateCheck applies the given mutation to the uptime provider. Creates/updates are skipped when the
+// hash of the check equals the hash of the check as last applied. Returns the hash of the check when
+// it's successfully created/updated (or unchanged), and whether the mutation was successfully applied.
+func (r *UptimeCheckService) mutateCheck(ctx context.Context, mutation m.Mutation, check *m.UptimeCheck,
+	lastApplied m.LastApplied, now time.Time) (requeueAfter time.Duration, hash string, ok bool) {
+	if mutation == m.Delete {
+		return 0, "", r.deleteCheck(ctx, check)
+	}
+	requeueAfter = r.handleMaintenance(check, now)
+	hash = check.Hash(now, r.hashKey)
+	if hash == lastApplied.Hashes[check.ID] {
+		log.FromContext(ctx).V(1).Info("uptime check unchanged, skipping update", "check", check.ID)
+		return requeueAfter, hash, true
+	}
+	if !lastApplied.WasApplied(check.ID) {
+		// check wasn't applied by the operator before
+		r.adoptUnmanagedCheck(ctx, check)
+	}
+	if r.dryRun {
+		r.dryRunMutation(ctx, mutation, check)
+		return requeueAfter, "", false
+	}
+	err := r.withProvider(ctx, func() error { return r.provider.CreateOrUpdateCheck(ctx, *check) })
+	r.logMutation(ctx, err, mutation, check)
+	if err != nil {
+		return requeueAfter, "", false
+	}
+	if check.Maintenance == nil && lastApplied.HadMaintenanceWindow(check.ID) {
+		if err = r.deleteMaintenanceWindows(ctx, check); err != nil {
+			log.FromContext(ctx).Error(err, "failed to delete maintenance window", "check", check.ID)
+			return requeueAfter, "", false
+		}
+	}
+	if lastApplied.WasApplied(check.ID) && lastApplied.WasPaused(check.ID) != check.Paused {
+		r.logPausedTransition(ctx, check)
+	}
+	return requeueAfter, hash, true
+}
+
+// deleteCheck deletes the given check from the uptime provider, when deletes are enabled.
+// Returns whether the check is actually deleted.
+func (r *UptimeCheckService) deleteCheck(ctx context.Context, check *m.UptimeCheck) bool {
+	if !r.enableDeletes {
+		r.logDeleteDisabled(ctx, check)
+		return false
+	}
+	if r.dryRun {
+		r.dryRunMutation(ctx, m.Delete, check)
+		return false
+	}
+	err := r.withProvider(ctx, func() error { return r.provider.DeleteCheck(ctx, *check) })
+	r.logMutation(ctx, err, m.Delete, check)
+	return err == nil
+}
+
+// withProvider calls the given func once the number of concurrent calls to the uptime provider allows it
+func (r *UptimeCheckService) withProvider(ctx context.Context, call func() error) error {
+	if r.providerSemaphore == nil {
+		return call()
+	}
+	select {
+	case r.providerSemaphore <- struct{}{}:
+		defer func() { <-r.providerSemaphore }()
+		return call()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// roundInterval rounds the interval of the check to the nearest interval supported by the provider,
+// returns a warning when the interval was rounded.
+func (r *UptimeCheckService) roundInterval(check *m.UptimeCheck) []string {
+	supporter, ok := r.provider.(IntervalSupporter)
+	if !ok {
+		return nil
+	}
+	interval := p.NearestInterval(check.Interval, supporter.SupportedIntervals())
+	if interval == check.Interval {
+		return nil
+	}
+	warning := fmt.Sprintf("interval %s of uptime check '%s' (id: %s) isn't supported by the uptime provider, "+
+		"using %s instead. Supported intervals: %v.", check.Interval, check.Name, check.ID, interval, supporter.SupportedIntervals())
+	check.Interval = interval
+	return []string{warning}
+}
+
+// approximateThresholds returns warnings about the thresholds of the given check which the provider can't apply exactly
+func (r *UptimeCheckService) approximateThresholds(check m.UptimeCheck) []string {
+	if approximator, ok := r.provider.(ThresholdApproximator); ok {
+		return approximator.ApproximatedThresholds(check)
+	}
+	return nil
+}
+
+// handleMaintenance pauses the check during its maintenance window when the provider can't handle
+// the window itself. Returns the duration until the window starts or ends, so the check is revisited then.
+func (r *UptimeCheckService) handleMaintenance(check *m.UptimeCheck, now time.Time) time.Duration {
+	if check.Maintenance == nil {
+		return 0
+	}
+	window := *check.Maintenance
+	supporter, ok := r.provider.(MaintenanceWindowSupporter)
+	if !ok || !supporter.SupportsMaintenanceWindow(window) {
+		check.Paused = check.Paused || window.IsActive(now)
+		check.Maintenance = nil
+	}
+	transition, ok := window.NextTransition(now)
+	if !ok {
+		return 0
+	}
+	return transition.Sub(now)
+}
+
+// deleteMaintenanceWindows removes the maintenance window(s) of the given check from the provider,
+// when the provider registers maintenance windows separately from the check itself
+func (r *UptimeCheckService) deleteMaintenanceWindows(ctx context.Context, check *m.UptimeCheck) error {
+	remover, ok := r.provider.(MaintenanceWindowRemover)
+	if !ok {
+		return nil
+	}
+	return r.withProvider(ctx, func() error { return remover.DeleteMaintenanceWindows(ctx, *check) })
+}
+
+// logPausedTransition reports when a check transitions from active to paused, or vice versa
+func (r *UptimeCheckService) logPausedTransition(ctx context.Context, check *m.UptimeCheck) {
+	state := "resumed"
+	emoji := ":arrow_forward:"
+	if check.Paused {
+		state = "paused"
+		emoji = ":double_vertical_bar:"
+	}
+	msg := fmt.Sprintf("uptime check '%s' (id: %s) %s.", check.Name, check.ID, state)
+	log.FromContext(ctx).Info(msg)
+	if r.slack == nil {
+		return
+	}
+	r.slack.Send(ctx, emoji+" "+msg)
+}
+
+// ReportDuplicateID reports that the given ingress route isn't processed because one of
+// its uptime check IDs is already in use by another ingress route.
+func (r *UptimeCheckService) ReportDuplicateID(ctx context.Context, checkID string, route string, otherRoute string) {
+	msg := fmt.Sprintf("uptime check ID '%s' of ingress route %s is already in use by ingress route %s. "+
+		"Refusing to mutate uptime check(s) of %s, please assign a unique ID.", checkID, route, otherRoute, route)
+	log.FromContext(ctx).Info(msg)
+	if r.slack == nil {
+		return
+	}
+	r.slack.Send(ctx, ":large_red_square: "+msg)
+}
+
+func (r *UptimeCheckService) logWarnings(ctx context.Context, warnings []string) {
+	for _, warning := range warnings {
+		log.FromContext(ctx).Info(warning)
 	}
 }
 
@@ -0,0 +1,520 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	m "github.com/PDOK/uptime-operator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type testProvider struct {
+	checks  map[string]m.UptimeCheck
+	updates int
+}
+
+func newTestProvider() *testProvider {
+	return &testProvider{checks: make(map[string]m.UptimeCheck)}
+}
+
+func (t *testProvider) CreateOrUpdateCheck(_ context.Context, check m.UptimeCheck) error {
+	t.checks[check.ID] = check
+	t.updates++
+	return nil
+}
+
+func (t *testProvider) DeleteCheck(_ context.Context, check m.UptimeCheck) error {
+	delete(t.checks, check.ID)
+	return nil
+}
+
+func (t *testProvider) ListCheckIDs(_ context.Context, tag string) ([]string, error) {
+	var result []string
+	for id, check := range t.checks {
+		if tag == "" || slices.Contains(check.Tags, tag) {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+type intervalTestProvider struct {
+	*testProvider
+}
+
+func (t *intervalTestProvider) SupportedIntervals() []time.Duration {
+	return []time.Duration{time.Minute, 5 * time.Minute}
+}
+
+type regionTestProvider struct {
+	*testProvider
+}
+
+func (t *regionTestProvider) SupportedRegions() []string {
+	return []string{"eu", "na"}
+}
+
+type maintenanceTestProvider struct {
+	*testProvider
+	deletedWindows []string
+}
+
+func (t *maintenanceTestProvider) SupportsMaintenanceWindow(_ m.MaintenanceWindow) bool {
+	return true
+}
+
+func (t *maintenanceTestProvider) DeleteMaintenanceWindows(_ context.Context, check m.UptimeCheck) error {
+	t.deletedWindows = append(t.deletedWindows, check.ID)
+	return nil
+}
+
+func TestUptimeCheckService_Mutate_RemovesStaleChecks(t *testing.T) {
+	provider := newTestProvider()
+	service := New(WithProvider(provider), WithDeletes(true))
+
+	annotations := map[string]string{
+		m.AnnotationID:   "old-id",
+		m.AnnotationName: "Test Check",
+		m.AnnotationURL:  "https://pdok.example",
+	}
+	result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Contains(t, provider.checks, "old-id")
+	assert.Equal(t, []string{"old-id"}, result.LastApplied.IDs)
+
+	// change ID of check
+	annotations[m.AnnotationID] = "new-id"
+	annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	result = service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.NotContains(t, provider.checks, "old-id")
+	assert.Contains(t, provider.checks, "new-id")
+	assert.Equal(t, []string{"new-id"}, result.LastApplied.IDs)
+
+	// invalid annotations shouldn't result in removal of checks
+	delete(annotations, m.AnnotationURL)
+	annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	result = service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Contains(t, provider.checks, "new-id")
+	assert.Equal(t, []string{"new-id"}, result.LastApplied.IDs)
+}
+
+func TestUptimeCheckService_Mutate_KeepsStaleChecksUntilDeleted(t *testing.T) {
+	provider := newTestProvider()
+	provider.checks["old-id"] = m.UptimeCheck{ID: "old-id"}
+	annotations := map[string]string{
+		m.AnnotationID:          "new-id",
+		m.AnnotationName:        "Test Check",
+		m.AnnotationURL:         "https://pdok.example",
+		m.AnnotationLastApplied: `{"ids":["old-id"]}`,
+	}
+
+	// deletes disabled, so the previous check should be remembered
+	result := New(WithProvider(provider)).Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Contains(t, provider.checks, "old-id")
+	assert.Equal(t, []string{"new-id", "old-id"}, result.LastApplied.IDs)
+
+	annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	result = New(WithProvider(provider), WithDeletes(true)).Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.NotContains(t, provider.checks, "old-id")
+	assert.Equal(t, []string{"new-id"}, result.LastApplied.IDs)
+}
+
+type splitterTestProvider struct {
+	*testProvider
+	maxCheckIDLength int
+}
+
+func (t *splitterTestProvider) MaxCheckIDLength() int {
+	return t.maxCheckIDLength
+}
+
+func TestUptimeCheckService_Mutate_SplitsStringAssertions(t *testing.T) {
+	provider := &splitterTestProvider{testProvider: newTestProvider()}
+	service := New(WithProvider(provider), WithDeletes(true))
+
+	annotations := map[string]string{
+		m.AnnotationID:                "id",
+		m.AnnotationName:              "Test Check",
+		m.AnnotationURL:               "https://pdok.example",
+		m.AnnotationStringContains:    "OK",
+		m.AnnotationStringNotContains: "Exception",
+	}
+	result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, []string{"id", "id-not-contains"}, result.LastApplied.IDs)
+	if assert.Contains(t, provider.checks, "id") {
+		assert.Equal(t, "OK", provider.checks["id"].StringContains)
+		assert.Empty(t, provider.checks["id"].StringNotContains)
+	}
+	if assert.Contains(t, provider.checks, "id-not-contains") {
+		assert.Empty(t, provider.checks["id-not-contains"].StringContains)
+		assert.Equal(t, "Exception", provider.checks["id-not-contains"].StringNotContains)
+	}
+
+	// removing one of the assertions removes the additional check
+	delete(annotations, m.AnnotationStringNotContains)
+	annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	result = service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Empty(t, result.Warnings)
+	assert.Contains(t, provider.checks, "id")
+	assert.NotContains(t, provider.checks, "id-not-contains")
+}
+
+func TestUptimeCheckService_Mutate_DoesNotSplitStringAssertions(t *testing.T) {
+	annotations := map[string]string{
+		m.AnnotationID:                "id",
+		m.AnnotationName:              "Test Check",
+		m.AnnotationURL:               "https://pdok.example",
+		m.AnnotationStringContains:    "OK",
+		m.AnnotationStringNotContains: "Exception",
+	}
+	tests := []struct {
+		name         string
+		provider     UptimeProvider
+		wantWarnings int
+	}{
+		{
+			name:     "Provider combines string assertions",
+			provider: newTestProvider(),
+		},
+		{
+			name:         "ID of additional check too long",
+			provider:     &splitterTestProvider{testProvider: newTestProvider(), maxCheckIDLength: len("id-not-contains") - 1},
+			wantWarnings: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := New(WithProvider(tt.provider))
+			result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+			assert.Len(t, result.Warnings, tt.wantWarnings)
+			assert.Equal(t, []string{"id"}, result.LastApplied.IDs)
+		})
+	}
+}
+
+func TestUptimeCheckService_Mutate_RejectsUnsupportedResponseAssertions(t *testing.T) {
+	provider := newTestProvider()
+	service := New(WithProvider(provider), WithDeletes(true))
+
+	annotations := map[string]string{
+		m.AnnotationID:   "id",
+		m.AnnotationName: "Test Check",
+		m.AnnotationURL:  "https://pdok.example",
+	}
+	result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, 1, provider.updates)
+
+	annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	annotations[m.AnnotationExpectedStatusCodes] = "200"
+	annotations[m.AnnotationJSONPath] = "$.status"
+	result = service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, 1, provider.updates, "check with unsupported assertions shouldn't be applied")
+	assert.Contains(t, provider.checks, "id", "check with unsupported assertions shouldn't be deleted")
+	assert.Equal(t, []string{"id"}, result.LastApplied.IDs)
+}
+
+func TestUptimeCheckService_Mutate_AppliesDefaults(t *testing.T) {
+	provider := newTestProvider()
+	service := New(WithProvider(provider), WithDefaults(m.CheckDefaults{Regions: []string{"eu"}}))
+
+	annotations := map[string]string{
+		m.AnnotationID:   "id",
+		m.AnnotationName: "Test Check",
+		m.AnnotationURL:  "https://pdok.example",
+	}
+	service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, []string{"eu"}, provider.checks["id"].Regions)
+
+	annotations[m.AnnotationRegions] = "EU, NA"
+	service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, []string{"eu", "na"}, provider.checks["id"].Regions)
+}
+
+func TestUptimeCheckService_Mutate_IgnoresUnsupportedRegions(t *testing.T) {
+	provider := &regionTestProvider{newTestProvider()}
+	service := New(WithProvider(provider))
+
+	annotations := map[string]string{
+		m.AnnotationID:      "id",
+		m.AnnotationName:    "Test Check",
+		m.AnnotationURL:     "https://pdok.example",
+		m.AnnotationRegions: "eu,mars",
+	}
+	result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	if assert.Len(t, result.Warnings, 1) {
+		assert.Contains(t, result.Warnings[0], "uses region 'mars'")
+	}
+	assert.Equal(t, []string{"eu"}, provider.checks["id"].Regions)
+}
+
+func TestUptimeCheckService_ValidateDefaultRegions(t *testing.T) {
+	provider := &regionTestProvider{newTestProvider()}
+	assert.NoError(t, New(WithProvider(provider), WithDefaults(m.CheckDefaults{Regions: []string{"EU", "na"}})).ValidateDefaultRegions())
+	assert.Error(t, New(WithProvider(provider), WithDefaults(m.CheckDefaults{Regions: []string{"mars"}})).ValidateDefaultRegions())
+	assert.NoError(t, New(WithProvider(newTestProvider()), WithDefaults(m.CheckDefaults{Regions: []string{"mars"}})).ValidateDefaultRegions())
+}
+
+func TestUptimeCheckService_Mutate_RoundsInterval(t *testing.T) {
+	provider := &intervalTestProvider{newTestProvider()}
+	service := New(WithProvider(provider))
+
+	annotations := map[string]string{
+		m.AnnotationID:       "id",
+		m.AnnotationName:     "Test Check",
+		m.AnnotationURL:      "https://pdok.example",
+		m.AnnotationInterval: "4m",
+	}
+	result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	if assert.Len(t, result.Warnings, 1) {
+		assert.Contains(t, result.Warnings[0], "using 5m0s instead")
+	}
+	assert.Equal(t, 5*time.Minute, provider.checks["id"].Interval)
+
+	annotations[m.AnnotationInterval] = "5m"
+	result = service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestUptimeCheckService_Mutate_SkipsUnchangedChecks(t *testing.T) {
+	provider := newTestProvider()
+	service := New(WithProvider(provider))
+
+	annotations := map[string]string{
+		m.AnnotationID:   "id",
+		m.AnnotationName: "Test Check",
+		m.AnnotationURL:  "https://pdok.example",
+	}
+	result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, 1, provider.updates)
+	assert.Contains(t, result.LastApplied.Hashes, "id")
+
+	annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	result = service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, 1, provider.updates)
+	assert.Equal(t, annotations[m.AnnotationLastApplied], result.LastApplied.String())
+
+	annotations[m.AnnotationTags] = "changed"
+	result = service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, 2, provider.updates)
+	assert.NotEqual(t, annotations[m.AnnotationLastApplied], result.LastApplied.String())
+}
+
+func TestUptimeCheckService_Mutate_DeletesRemovedMaintenanceWindows(t *testing.T) {
+	provider := &maintenanceTestProvider{testProvider: newTestProvider()}
+	service := New(WithProvider(provider))
+
+	annotations := map[string]string{
+		m.AnnotationID:                  "id",
+		m.AnnotationName:                "Test Check",
+		m.AnnotationURL:                 "https://pdok.example",
+		m.AnnotationMaintenanceCron:     "0 2 * * *",
+		m.AnnotationMaintenanceDuration: "1h",
+	}
+	result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.True(t, result.LastApplied.HadMaintenanceWindow("id"))
+	assert.Empty(t, provider.deletedWindows)
+
+	annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	delete(annotations, m.AnnotationMaintenanceCron)
+	delete(annotations, m.AnnotationMaintenanceDuration)
+	result = service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.False(t, result.LastApplied.HadMaintenanceWindow("id"))
+	assert.Equal(t, []string{"id"}, provider.deletedWindows)
+
+	annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	annotations[m.AnnotationTags] = "changed"
+	service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, []string{"id"}, provider.deletedWindows, "window shouldn't be deleted again")
+}
+
+func TestUptimeCheckService_DeleteOrphanedChecks(t *testing.T) {
+	provider := &splitterTestProvider{testProvider: newTestProvider()}
+	for _, id := range []string{"in-use", "in-use-not-contains", "previous", "orphan"} {
+		provider.checks[id] = m.UptimeCheck{ID: id, Tags: []string{"cluster-a"}}
+	}
+	provider.checks["other-cluster"] = m.UptimeCheck{ID: "other-cluster", Tags: []string{"cluster-b"}}
+	routes := map[string]map[string]string{
+		"route-a": {
+			m.AnnotationID:                "in-use",
+			m.AnnotationName:              "Test Check",
+			m.AnnotationURL:               "https://pdok.example",
+			m.AnnotationStringContains:    "OK",
+			m.AnnotationStringNotContains: "Error",
+			m.AnnotationLastApplied:       `{"ids":["previous"]}`,
+		},
+		"route-without-checks": {"other": "annotation"},
+	}
+	options := []UptimeCheckOption{WithProvider(provider), WithDeletes(true), WithOwnerTag("cluster-a")}
+
+	err := New(options...).DeleteOrphanedChecks(context.TODO(), routes)
+	assert.NoError(t, err)
+	assert.Len(t, provider.checks, 5, "orphan deletes are disabled")
+
+	err = New(WithProvider(provider), WithDeletes(true), WithOrphanDeletes(true)).DeleteOrphanedChecks(context.TODO(), routes)
+	assert.NoError(t, err)
+	assert.Len(t, provider.checks, 5, "owner tag is required")
+
+	err = New(append(options, WithOrphanDeletes(true))...).DeleteOrphanedChecks(context.TODO(), routes)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"in-use", "in-use-not-contains", "previous", "other-cluster"},
+		slices.Collect(maps.Keys(provider.checks)))
+
+	routes["route-b"] = map[string]string{m.AnnotationID: "invalid"}
+	provider.checks["orphan"] = m.UptimeCheck{ID: "orphan", Tags: []string{"cluster-a"}}
+	err = New(append(options, WithOrphanDeletes(true))...).DeleteOrphanedChecks(context.TODO(), routes)
+	assert.NoError(t, err)
+	assert.Contains(t, provider.checks, "orphan", "invalid annotations should prevent deletes")
+}
+
+func TestUptimeCheckService_Mutate_AddsOwnerTag(t *testing.T) {
+	provider := newTestProvider()
+	service := New(WithProvider(provider), WithOwnerTag("cluster-a"))
+
+	annotations := map[string]string{
+		m.AnnotationID:   "id",
+		m.AnnotationName: "Test Check",
+		m.AnnotationURL:  "https://pdok.example",
+		m.AnnotationTags: "tag",
+	}
+	service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Equal(t, []string{"tag", m.TagManagedBy, "cluster-a"}, provider.checks["id"].Tags)
+}
+
+type slowTestProvider struct {
+	calls, maxCalls atomic.Int32
+}
+
+func (t *slowTestProvider) CreateOrUpdateCheck(_ context.Context, _ m.UptimeCheck) error {
+	calls := t.calls.Add(1)
+	defer t.calls.Add(-1)
+	for {
+		maxCalls := t.maxCalls.Load()
+		if calls <= maxCalls || t.maxCalls.CompareAndSwap(maxCalls, calls) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func (t *slowTestProvider) DeleteCheck(_ context.Context, _ m.UptimeCheck) error {
+	return nil
+}
+
+func TestUptimeCheckService_Mutate_LimitsProviderConcurrency(t *testing.T) {
+	provider := &slowTestProvider{}
+	service := New(WithProvider(provider), WithProviderConcurrency(2))
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			annotations := map[string]string{
+				m.AnnotationID:   fmt.Sprintf("id-%d", i),
+				m.AnnotationName: "Test Check",
+				m.AnnotationURL:  "https://pdok.example",
+			}
+			service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), provider.maxCalls.Load())
+}
+
+func TestUptimeCheckService_Mutate_DryRun(t *testing.T) {
+	provider := newTestProvider()
+	service := New(WithProvider(provider), WithDeletes(true), WithDryRun(true))
+
+	annotations := map[string]string{
+		m.AnnotationID:          "id",
+		m.AnnotationName:        "Test Check",
+		m.AnnotationURL:         "https://pdok.example",
+		m.AnnotationLastApplied: `{"ids":["old-id"]}`,
+	}
+	provider.checks["old-id"] = m.UptimeCheck{ID: "old-id"}
+	result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+	assert.Nil(t, result.LastApplied, "nothing is applied in dry-run mode")
+	assert.Equal(t, 0, provider.updates)
+	assert.Contains(t, provider.checks, "old-id")
+
+	service.Mutate(context.TODO(), m.Delete, "test-ingress", annotations, nil)
+	assert.Contains(t, provider.checks, "old-id")
+}
+
+// slackRecorder fake Slack webhook which records the posted messages
+type slackRecorder struct {
+	*httptest.Server
+	lock     sync.Mutex
+	messages []string
+}
+
+func newSlackRecorder(t *testing.T) *slackRecorder {
+	recorder := &slackRecorder{}
+	recorder.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct {
+			Text string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		recorder.lock.Lock()
+		recorder.messages = append(recorder.messages, message.Text)
+		recorder.lock.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(recorder.Close)
+	return recorder
+}
+
+// messagesContaining returns (and forgets) the recorded messages containing the given text
+func (s *slackRecorder) messagesContaining(text string) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var result []string
+	for _, message := range s.messages {
+		if strings.Contains(message, text) {
+			result = append(result, message)
+		}
+	}
+	s.messages = nil
+	return result
+}
+
+func TestUptimeCheckService_Mutate_ReportsPausedTransitions(t *testing.T) {
+	slack := newSlackRecorder(t)
+	provider := newTestProvider()
+	annotations := map[string]string{
+		m.AnnotationID:   "id",
+		m.AnnotationName: "Test Check",
+		m.AnnotationURL:  "https://pdok.example",
+	}
+	mutate := func() {
+		// new service for every mutation, as if the operator restarted in between
+		service := New(WithProvider(provider), WithSlack(slack.URL, "channel"))
+		result := service.Mutate(context.TODO(), m.CreateOrUpdate, "test-ingress", annotations, nil)
+		annotations[m.AnnotationLastApplied] = result.LastApplied.String()
+	}
+
+	mutate()
+	assert.Empty(t, slack.messagesContaining("paused"), "no transition on first apply")
+
+	annotations[m.AnnotationPaused] = "true"
+	mutate()
+	assert.Equal(t, []string{":double_vertical_bar: uptime check 'Test Check' (id: id) paused."}, slack.messagesContaining("paused"))
+
+	mutate()
+	assert.Empty(t, slack.messagesContaining("paused"), "no transition when unchanged")
+
+	annotations[m.AnnotationPaused] = "false"
+	mutate()
+	assert.Equal(t, []string{":arrow_forward: uptime check 'Test Check' (id: id) resumed."}, slack.messagesContaining("resumed"))
+}